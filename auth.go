@@ -0,0 +1,235 @@
+package fortnite
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+//DeviceAuth holds a set of device credentials that can be used to log in to an account without
+//its password or two-factor code.
+type DeviceAuth struct {
+	AccountID string `json:"accountId"`
+	DeviceID  string `json:"deviceId"`
+	Secret    string `json:"secret"`
+}
+
+//OauthDeviceAuthRequest is used to marshal a JSON payload to log in with a DeviceAuth.
+type OauthDeviceAuthRequest struct {
+	GrantType    string `json:"grant_type"`
+	AccountID    string `json:"account_id"`
+	DeviceID     string `json:"device_id"`
+	Secret       string `json:"secret"`
+	IncludePerms bool   `json:"includePerms"`
+	TokenType    string `json:"token_type"`
+}
+
+//OauthAuthorizationCodeRequest is used to marshal a JSON payload to log in with an authorization
+//code obtained from Epic's website.
+type OauthAuthorizationCodeRequest struct {
+	GrantType    string `json:"grant_type"`
+	Code         string `json:"code"`
+	IncludePerms bool   `json:"includePerms"`
+	TokenType    string `json:"token_type"`
+}
+
+//OauthOTPRequest is used to marshal a JSON payload to answer a two-factor authentication challenge.
+type OauthOTPRequest struct {
+	GrantType    string `json:"grant_type"`
+	OTP          string `json:"otp"`
+	Challenge    string `json:"challenge"`
+	IncludePerms bool   `json:"includePerms"`
+}
+
+//CompleteTwoFactor finishes a Login that was interrupted by a TwoFactorRequired error, using the
+//code sent to the user by email or generated by their authenticator app.
+func (c *Client) CompleteTwoFactor(ctx context.Context, challenge *TwoFactorRequired, code string) error {
+	if challenge == nil {
+		return fmt.Errorf("fortnite: two-factor request: no challenge to complete: %w", ErrAuthFailed)
+	}
+
+	var accessTokenResponse OauthTokenRequestResponse
+
+	err := c.send(ctx, request{
+		method:   http.MethodPost,
+		url:      c.endpoints().oauthToken(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {basicAuth(c.FortniteClientToken)}},
+		form: OauthOTPRequest{
+			GrantType:    "otp",
+			OTP:          code,
+			Challenge:    challenge.Challenge,
+			IncludePerms: true,
+		},
+	}, &accessTokenResponse)
+
+	if err != nil {
+		return fmt.Errorf("fortnite: two-factor request: %w", err)
+	}
+
+	if accessTokenResponse.AccessToken == "" {
+		return fmt.Errorf("fortnite: two-factor request: %w", ErrAuthFailed)
+	}
+
+	return c.exchangeLogin(ctx, accessTokenResponse.AccessToken)
+}
+
+//LoginWithDeviceAuth logs in using device credentials previously created with CreateDeviceAuth.
+//The DeviceAuth is kept on the client so it can log in again once the refresh token expires.
+func (c *Client) LoginWithDeviceAuth(ctx context.Context, deviceAuth DeviceAuth) error {
+	err := c.grant(ctx, OauthDeviceAuthRequest{
+		GrantType:    "device_auth",
+		AccountID:    deviceAuth.AccountID,
+		DeviceID:     deviceAuth.DeviceID,
+		Secret:       deviceAuth.Secret,
+		IncludePerms: true,
+		TokenType:    "egl",
+	})
+
+	if err != nil {
+		return fmt.Errorf("fortnite: device auth login: %w", err)
+	}
+
+	c.mu.Lock()
+	c.DeviceAuth = &deviceAuth
+	c.mu.Unlock()
+
+	return nil
+}
+
+//LoginWithExchangeCode logs in using an exchange code issued for the Fortnite client, for example
+//by another session of the same account.
+func (c *Client) LoginWithExchangeCode(ctx context.Context, exchangeCode string) error {
+	err := c.grant(ctx, OauthTokenExchangeRequest{
+		GrantType:    "exchange_code",
+		ExchangeCode: exchangeCode,
+		IncludePerms: true,
+		TokenType:    "egl",
+	})
+
+	if err != nil {
+		return fmt.Errorf("fortnite: exchange code login: %w", err)
+	}
+
+	return nil
+}
+
+//LoginWithAuthorizationCode logs in using an authorization code that the account owner obtained
+//from Epic's website for the Fortnite client.
+func (c *Client) LoginWithAuthorizationCode(ctx context.Context, code string) error {
+	err := c.grant(ctx, OauthAuthorizationCodeRequest{
+		GrantType:    "authorization_code",
+		Code:         code,
+		IncludePerms: true,
+		TokenType:    "egl",
+	})
+
+	if err != nil {
+		return fmt.Errorf("fortnite: authorization code login: %w", err)
+	}
+
+	return nil
+}
+
+//CreateDeviceAuth creates device credentials for the logged in account. Store the result securely
+//and pass it to LoginWithDeviceAuth instead of keeping the account's email and password around.
+func (c *Client) CreateDeviceAuth(ctx context.Context) (DeviceAuth, error) {
+	accountID, err := c.loggedInAccountID(ctx)
+
+	if err != nil {
+		return DeviceAuth{}, err
+	}
+
+	var response DeviceAuth
+
+	err = c.sendAuthorized(ctx, request{
+		method:   http.MethodPost,
+		url:      c.endpoints().deviceAuth(accountID),
+		service:  ServiceAccount,
+		endpoint: EndpointDeviceAuth,
+	}, &response)
+
+	if err != nil {
+		return DeviceAuth{}, err
+	}
+
+	return response, nil
+}
+
+//DeleteDeviceAuth revokes the device credentials with the given device id from the logged in
+//account.
+func (c *Client) DeleteDeviceAuth(ctx context.Context, deviceID string) error {
+	accountID, err := c.loggedInAccountID(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return c.sendAuthorized(ctx, request{
+		method:   http.MethodDelete,
+		url:      c.endpoints().deleteDeviceAuth(accountID, deviceID),
+		service:  ServiceAccount,
+		endpoint: EndpointDeviceAuth,
+	}, nil)
+}
+
+//grant exchanges the given OAuth grant for a session with the Fortnite client and stores the
+//resulting tokens.
+func (c *Client) grant(ctx context.Context, form interface{}) error {
+	var tokenResponse OauthTokenResponse
+
+	err := c.send(ctx, request{
+		method:   http.MethodPost,
+		url:      c.endpoints().oauthToken(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {basicAuth(c.FortniteClientToken)}},
+		form:     form,
+	}, &tokenResponse)
+
+	if err != nil {
+		return err
+	}
+
+	if tokenResponse.AccessToken == "" {
+		return ErrAuthFailed
+	}
+
+	return c.setTokens(ctx, tokenResponse)
+}
+
+//relogin logs in again with whichever long-lived credentials the client holds, preferring its
+//DeviceAuth over an email and password.
+func (c *Client) relogin(ctx context.Context) error {
+	c.mu.RLock()
+	deviceAuth := c.DeviceAuth
+	c.mu.RUnlock()
+
+	if deviceAuth != nil {
+		return c.LoginWithDeviceAuth(ctx, *deviceAuth)
+	}
+
+	if c.Email == "" {
+		return fmt.Errorf("%w: no credentials available to log in again", ErrAuthFailed)
+	}
+
+	return c.Login(ctx)
+}
+
+//loggedInAccountID returns the id of the account the client is logged in as, logging in first
+//if there is no session yet.
+func (c *Client) loggedInAccountID(ctx context.Context) (string, error) {
+	if _, err := c.accessToken(ctx); err != nil {
+		return "", err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.AccountID == "" {
+		return "", fmt.Errorf("%w: session has no account id", ErrAuthFailed)
+	}
+
+	return c.AccountID, nil
+}
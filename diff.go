@@ -0,0 +1,110 @@
+package fortnite
+
+import (
+	"fmt"
+	"sort"
+)
+
+//StatsDiff holds the change in a player's stats between two snapshots, such as the results of a
+//single play session. Its derived stats, like KdRatio and WinPercentage, are calculated from the
+//change in the counters, so they describe the period between the snapshots alone.
+type StatsDiff struct {
+	//Modes holds the change in every mode present in either snapshot.
+	Modes map[Mode]ModeStats `json:"modes"`
+
+	//LifetimeStats holds the change in the solo, duo and squad totals.
+	LifetimeStats ModeStats `json:"lifetime_stats"`
+}
+
+//Diff returns the change in stats from before to after, which must be snapshots of the same
+//account, platform and window. If any counter has gone down, which happens when Epic wipes or
+//resets stats, it returns an error matching ErrStatsReset; take a new snapshot as the baseline.
+func Diff(before FormattedBRStats, after FormattedBRStats) (StatsDiff, error) {
+	if before.Info.AccountID != after.Info.AccountID || before.Info.Platform != after.Info.Platform ||
+		before.Info.Window.String() != after.Info.Window.String() {
+		return StatsDiff{}, fmt.Errorf("fortnite: cannot diff stats of different accounts, platforms or windows")
+	}
+
+	diff := StatsDiff{
+		Modes:         map[Mode]ModeStats{},
+		LifetimeStats: after.LifetimeStats.Sub(before.LifetimeStats),
+	}
+
+	for mode := range before.Modes {
+		diff.Modes[mode] = ModeStats{}
+	}
+
+	for mode := range after.Modes {
+		diff.Modes[mode] = ModeStats{}
+	}
+
+	modes := make([]string, 0, len(diff.Modes))
+
+	for mode := range diff.Modes {
+		modes = append(modes, string(mode))
+	}
+
+	sort.Strings(modes)
+
+	for _, name := range modes {
+		mode := Mode(name)
+		delta := after.Modes[mode].Sub(before.Modes[mode])
+
+		if stat, ok := delta.decreased(); ok {
+			return StatsDiff{}, fmt.Errorf("%w: %v %v went from %v to %v", ErrStatsReset, mode, stat,
+				before.Modes[mode].counter(stat), after.Modes[mode].counter(stat))
+		}
+
+		diff.Modes[mode] = delta
+	}
+
+	return diff, nil
+}
+
+//counterNames lists the counters of a ModeStats by their JSON names.
+var counterNames = []string{
+	"wins", "top3", "top5", "top6", "top10", "top12", "top25", "matches", "kills", "time_played", "score", "solo_wins",
+}
+
+//counter returns the value of the counter with the given JSON name.
+func (s ModeStats) counter(name string) float64 {
+	switch name {
+	case "wins":
+		return s.Wins
+	case "top3":
+		return s.Top3
+	case "top5":
+		return s.Top5
+	case "top6":
+		return s.Top6
+	case "top10":
+		return s.Top10
+	case "top12":
+		return s.Top12
+	case "top25":
+		return s.Top25
+	case "matches":
+		return s.Matches
+	case "kills":
+		return s.Kills
+	case "time_played":
+		return s.TimePlayed
+	case "score":
+		return s.Score
+	case "solo_wins":
+		return s.SoloWins
+	}
+
+	return 0
+}
+
+//decreased returns the name of the first negative counter in a difference between two ModeStats.
+func (s ModeStats) decreased() (string, bool) {
+	for _, name := range counterNames {
+		if s.counter(name) < 0 {
+			return name, true
+		}
+	}
+
+	return "", false
+}
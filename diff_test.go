@@ -0,0 +1,151 @@
+package fortnite
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+//snapshot builds the stats of account-id on platform, with LifetimeStats totalled from the solo,
+//duo and squad modes given.
+func snapshot(platform Platform, window StatsWindow, modes map[Mode]ModeStats) FormattedBRStats {
+	var stats FormattedBRStats
+
+	stats.Info.AccountID = "account-id"
+	stats.Info.Platform = platform
+	stats.Info.Window = window
+	stats.Modes = map[Mode]ModeStats{}
+
+	for mode, s := range modes {
+		s.derive()
+		stats.Modes[mode] = s
+	}
+
+	stats.Group.Solo = stats.Modes[ModeSolo]
+	stats.Group.Duo = stats.Modes[ModeDuo]
+	stats.Group.Squad = stats.Modes[ModeSquad]
+	stats.LifetimeStats = Totals(stats.Group.Solo, stats.Group.Duo, stats.Group.Squad)
+
+	return stats
+}
+
+func TestDiff(t *testing.T) {
+	before := snapshot(PlatformPC, StatsWindowAllTime, map[Mode]ModeStats{
+		ModeSolo:            {Matches: 10, Wins: 1, SoloWins: 1, Kills: 12, TimePlayed: 200},
+		ModeDuo:             {Matches: 5, Top5: 2, Kills: 4},
+		ModeSquad:           {},
+		"playground_ltm_v2": {Matches: 2, Kills: 3},
+	})
+
+	after := snapshot(PlatformPC, StatsWindowAllTime, map[Mode]ModeStats{
+		ModeSolo:            {Matches: 13, Wins: 2, SoloWins: 2, Kills: 18, TimePlayed: 260},
+		ModeDuo:             {Matches: 5, Top5: 2, Kills: 4},
+		ModeSquad:           {Matches: 1, Kills: 2},
+		"playground_ltm_v2": {Matches: 2, Kills: 3},
+		"creative_ltm":      {Matches: 1, Kills: 1},
+	})
+
+	diff, err := Diff(before, after)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[Mode]counts{
+		ModeSolo:            {Matches: 3, Wins: 1, SoloWins: 1, Kills: 6},
+		ModeDuo:             {},
+		ModeSquad:           {Matches: 1, Kills: 2},
+		"playground_ltm_v2": {},
+		"creative_ltm":      {Matches: 1, Kills: 1},
+	}
+
+	if len(diff.Modes) != len(want) {
+		t.Errorf("got %v modes, want %v", len(diff.Modes), len(want))
+	}
+
+	for mode, want := range want {
+		if got := countsOf(diff.Modes[mode]); got != want {
+			t.Errorf("Modes[%v] = %+v, want %+v", mode, got, want)
+		}
+	}
+
+	if got, want := countsOf(diff.LifetimeStats), (counts{Matches: 4, Wins: 1, SoloWins: 1, Kills: 8}); got != want {
+		t.Errorf("LifetimeStats = %+v, want %+v", got, want)
+	}
+
+	if solo := diff.Modes[ModeSolo]; solo.TimePlayed != 60 || solo.KillsPerMatch != 2 || solo.Deaths != 2 {
+		t.Errorf("solo derived stats = %+v, want them calculated from the change alone", solo)
+	}
+}
+
+func TestDiffStatsReset(t *testing.T) {
+	before := snapshot(PlatformPC, StatsWindowAllTime, map[Mode]ModeStats{
+		ModeSolo:  {Matches: 10, Wins: 1, SoloWins: 1, Kills: 12},
+		ModeDuo:   {Matches: 5, Kills: 4},
+		ModeSquad: {},
+	})
+
+	tests := []struct {
+		name  string
+		modes map[Mode]ModeStats
+	}{
+		{"counter dropped", map[Mode]ModeStats{
+			ModeSolo:  {Matches: 11, Wins: 1, SoloWins: 1, Kills: 2},
+			ModeDuo:   {Matches: 5, Kills: 4},
+			ModeSquad: {},
+		}},
+		{"every counter wiped", map[Mode]ModeStats{
+			ModeSolo:  {},
+			ModeDuo:   {},
+			ModeSquad: {},
+		}},
+		{"mode disappeared", map[Mode]ModeStats{
+			ModeSolo:  {Matches: 10, Wins: 1, SoloWins: 1, Kills: 12},
+			ModeSquad: {},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Diff(before, snapshot(PlatformPC, StatsWindowAllTime, test.modes))
+
+			if !errors.Is(err, ErrStatsReset) {
+				t.Errorf("Diff() error = %v, want ErrStatsReset", err)
+			}
+		})
+	}
+}
+
+func TestDiffMismatch(t *testing.T) {
+	modes := map[Mode]ModeStats{ModeSolo: {Matches: 1}, ModeDuo: {}, ModeSquad: {}}
+	start := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	before := snapshot(PlatformPC, StatsWindowAllTime, modes)
+
+	otherAccount := snapshot(PlatformPC, StatsWindowAllTime, modes)
+	otherAccount.Info.AccountID = "other-account-id"
+
+	tests := []struct {
+		name   string
+		before FormattedBRStats
+		after  FormattedBRStats
+	}{
+		{"account", before, otherAccount},
+		{"platform", before, snapshot(PlatformPS4, StatsWindowAllTime, modes)},
+		{"window", before, snapshot(PlatformPC, StatsWindowWeekly, modes)},
+		{"custom window", snapshot(PlatformPC, StatsWindowSince(start), modes), snapshot(PlatformPC, StatsWindowSince(start.Add(time.Hour)), modes)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Diff(test.before, test.after)
+
+			if err == nil || errors.Is(err, ErrStatsReset) {
+				t.Errorf("Diff() error = %v, want a mismatch error", err)
+			}
+		})
+	}
+
+	if _, err := Diff(snapshot(PlatformPC, StatsWindowSince(start), modes), snapshot(PlatformPC, StatsWindowSince(start), modes)); err != nil {
+		t.Errorf("Diff() of the same custom window: %v", err)
+	}
+}
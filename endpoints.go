@@ -3,33 +3,135 @@ package fortnite
 import (
 	"fmt"
 	"net/url"
+	"strconv"
+	"time"
 )
 
+//Endpoints holds the base URLs of the Epic services the client talks to. Each Client has its own
+//Endpoints, so one can be pointed at a local mock server or a different Epic cluster without
+//affecting any other Client in the process. Empty fields fall back to DefaultEndpoints.
+type Endpoints struct {
+	//Account serves OAuth and account management.
+	Account string
+
+	//Persona serves display name lookups.
+	Persona string
+
+	//Lightswitch serves the Fortnite status.
+	Lightswitch string
+
+	//Content serves the Fortnite news.
+	Content string
+
+	//Fortnite serves stats, the store and PVE information.
+	Fortnite string
+}
+
+//DefaultEndpoints returns the base URLs of Epic's production services.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		Account:     "https://account-public-service-prod03.ol.epicgames.com",
+		Persona:     "https://persona-public-service-prod06.ol.epicgames.com",
+		Lightswitch: "https://lightswitch-public-service-prod06.ol.epicgames.com",
+		Content:     "https://fortnitecontent-website-prod07.ol.epicgames.com",
+		Fortnite:    "https://fortnite-public-service-prod11.ol.epicgames.com",
+	}
+}
+
+//withDefaults fills any empty base URL from DefaultEndpoints.
+func (e Endpoints) withDefaults() Endpoints {
+	defaults := DefaultEndpoints()
+
+	if e.Account == "" {
+		e.Account = defaults.Account
+	}
+	if e.Persona == "" {
+		e.Persona = defaults.Persona
+	}
+	if e.Lightswitch == "" {
+		e.Lightswitch = defaults.Lightswitch
+	}
+	if e.Content == "" {
+		e.Content = defaults.Content
+	}
+	if e.Fortnite == "" {
+		e.Fortnite = defaults.Fortnite
+	}
+
+	return e
+}
+
 //OAuth URLs
-var oauthTokenEndpoint = "https://account-public-service-prod03.ol.epicgames.com/account/api/oauth/token"
-var oauthExchangeEndpoint = "https://account-public-service-prod03.ol.epicgames.com/account/api/oauth/exchange"
-var oauthVerifyEndpoint = "https://account-public-service-prod03.ol.epicgames.com/account/api/oauth/verify?includePerms=true"
+func (e Endpoints) oauthToken() string {
+	return e.Account + "/account/api/oauth/token"
+}
+
+func (e Endpoints) oauthExchange() string {
+	return e.Account + "/account/api/oauth/exchange"
+}
+
+func (e Endpoints) oauthVerify() string {
+	return e.Account + "/account/api/oauth/verify?includePerms=true"
+}
+
+func (e Endpoints) killSession(token string) string {
+	return fmt.Sprintf("%v/account/api/oauth/sessions/kill/%v", e.Account, token)
+}
+
+func (e Endpoints) deviceAuth(accountID string) string {
+	return fmt.Sprintf("%v/account/api/public/account/%v/deviceAuth", e.Account, accountID)
+}
+
+func (e Endpoints) deleteDeviceAuth(accountID string, deviceID string) string {
+	return fmt.Sprintf("%v/account/api/public/account/%v/deviceAuth/%v", e.Account, accountID, deviceID)
+}
 
 //Require Authentication
-var fortniteStatusEndpoint = "https://lightswitch-public-service-prod06.ol.epicgames.com/lightswitch/api/service/bulk/status?serviceId=Fortnite"
-var fortniteNewsEndpoint = "https://fortnitecontent-website-prod07.ol.epicgames.com/content/api/pages/fortnite-game"
+func (e Endpoints) fortniteStatus() string {
+	return e.Lightswitch + "/lightswitch/api/service/bulk/status?serviceId=Fortnite"
+}
+
+func (e Endpoints) fortniteNews() string {
+	return e.Content + "/content/api/pages/fortnite-game"
+}
 
 //Do Not Require Authentication
-var fortnitePVEInfoEndpoint = "https://fortnite-public-service-prod11.ol.epicgames.com/fortnite/api/game/v2/world/info"
-var fortniteStoreEndpoint = "https://fortnite-public-service-prod11.ol.epicgames.com/fortnite/api/storefront/v2/catalog"
+func (e Endpoints) fortnitePVEInfo() string {
+	return e.Fortnite + "/fortnite/api/game/v2/world/info"
+}
+
+func (e Endpoints) fortniteStore() string {
+	return e.Fortnite + "/fortnite/api/storefront/v2/catalog"
+}
 
-func lookupURLEndpoint(username string) string {
-	return fmt.Sprintf("https://persona-public-service-prod06.ol.epicgames.com/persona/api/public/account/lookup?q=%v", url.QueryEscape(username))
+func (e Endpoints) lookup(username string) string {
+	return fmt.Sprintf("%v/persona/api/public/account/lookup?q=%v", e.Persona, url.QueryEscape(username))
 }
 
-func statsBattleRoyaleEndpoint(accountID string) string {
-	return fmt.Sprintf("https://fortnite-public-service-prod11.ol.epicgames.com/fortnite/api/stats/accountId/%v/bulk/window/alltime", accountID)
+func (e Endpoints) statsBattleRoyale(accountID string, window string) string {
+	return fmt.Sprintf("%v/fortnite/api/stats/accountId/%v/bulk/window/%v", e.Fortnite, accountID, window)
 }
 
-func statsPVEEndpoint(accountID string) string {
-	return fmt.Sprintf("https://fortnite-public-service-prod11.ol.epicgames.com/fortnite/api/game/v2/profile/%v/public/QueryProfile?profileId=profile0&rvn=-1", accountID)
+func (e Endpoints) statsV2(accountID string, start time.Time, end time.Time) string {
+	query := url.Values{}
+
+	if !start.IsZero() {
+		query.Set("startTime", strconv.FormatInt(start.Unix(), 10))
+	}
+
+	if !end.IsZero() {
+		query.Set("endTime", strconv.FormatInt(end.Unix(), 10))
+	}
+
+	statsURL := fmt.Sprintf("%v/fortnite/api/statsv2/account/%v", e.Fortnite, accountID)
+
+	if len(query) > 0 {
+		statsURL += "?" + query.Encode()
+	}
+
+	return statsURL
 }
 
-func killSessionEndpoint(token string) string {
-	return fmt.Sprintf("https://account-public-service-prod03.ol.epicgames.com/account/api/oauth/sessions/kill/%v", token)
+func (e Endpoints) statsPVE(accountID string) string {
+	return fmt.Sprintf("%v/fortnite/api/game/v2/profile/%v/public/QueryProfile?profileId=profile0&rvn=-1", e.Fortnite, accountID)
 }
@@ -0,0 +1,201 @@
+package fortnite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//ErrAuthFailed is returned when Epic rejects the credentials or access token used for a request.
+var ErrAuthFailed = errors.New("fortnite: authentication failed")
+
+//ErrNotFound is returned when the requested player or resource does not exist.
+var ErrNotFound = errors.New("fortnite: not found")
+
+//ErrRateLimited is returned when Epic has throttled the requests being made by the client.
+var ErrRateLimited = errors.New("fortnite: rate limited")
+
+//ErrInvalidPlatform is returned when a stats method is given a Platform the client does not know about.
+var ErrInvalidPlatform = errors.New("fortnite: invalid platform")
+
+//ErrStatsReset is returned by Diff when a stat has gone down between two snapshots, which happens
+//when Epic wipes or resets a player's stats.
+var ErrStatsReset = errors.New("fortnite: stats reset")
+
+//ErrUpstream is returned when Epic responds with an unexpected status code. Every UpstreamError
+//matches ErrUpstream when compared with errors.Is.
+var ErrUpstream = errors.New("fortnite: upstream error")
+
+//Error codes returned by Epic that the client knows how to interpret.
+const (
+	ErrorCodeInvalidGrant           = "errors.com.epicgames.account.oauth.invalid_grant"
+	ErrorCodeInvalidCredentials     = "errors.com.epicgames.account.invalid_account_credentials"
+	ErrorCodeInvalidRefreshToken    = "errors.com.epicgames.account.auth_token.invalid_refresh_token"
+	ErrorCodeExchangeCodeNotFound   = "errors.com.epicgames.account.oauth.exchange_code_not_found"
+	ErrorCodeAccountNotFound        = "errors.com.epicgames.account.account_not_found"
+	ErrorCodePersonaAccountNotFound = "errors.com.epicgames.persona.account_not_found"
+	ErrorCodeInvalidToken           = "errors.com.epicgames.common.oauth.invalid_token"
+	ErrorCodeTokenVerifyFailed      = "errors.com.epicgames.common.authentication.token_verification_failed"
+	ErrorCodeThrottled              = "errors.com.epicgames.common.throttled"
+	ErrorCodeTwoFactorRequired      = "errors.com.epicgames.common.two_factor_authentication.required"
+)
+
+//NumericErrorCodeInvalidToken is the numericErrorCode Epic returns alongside a rejected access token.
+const NumericErrorCodeInvalidToken = 1014
+
+//EpicError is the error envelope Epic's services return in the body of a failed request.
+type EpicError struct {
+	ErrorCode          string   `json:"errorCode"`
+	ErrorMessage       string   `json:"errorMessage"`
+	MessageVars        []string `json:"messageVars"`
+	NumericErrorCode   int      `json:"numericErrorCode"`
+	OriginatingService string   `json:"originatingService"`
+	Intent             string   `json:"intent"`
+}
+
+func (e *EpicError) Error() string {
+	return fmt.Sprintf("fortnite: %v (%v): %v", e.ErrorCode, e.NumericErrorCode, e.ErrorMessage)
+}
+
+//IsInvalidGrant indicates whether Epic rejected the credentials, refresh token or exchange code
+//used to obtain an access token.
+func (e *EpicError) IsInvalidGrant() bool {
+	switch e.ErrorCode {
+	case ErrorCodeInvalidGrant, ErrorCodeInvalidCredentials, ErrorCodeInvalidRefreshToken, ErrorCodeExchangeCodeNotFound:
+		return true
+	}
+
+	return false
+}
+
+//IsAccountNotFound indicates whether the requested account does not exist.
+func (e *EpicError) IsAccountNotFound() bool {
+	return e.ErrorCode == ErrorCodeAccountNotFound || e.ErrorCode == ErrorCodePersonaAccountNotFound
+}
+
+//IsTokenExpired indicates whether the access token used for the request is no longer valid.
+func (e *EpicError) IsTokenExpired() bool {
+	return e.ErrorCode == ErrorCodeInvalidToken || e.ErrorCode == ErrorCodeTokenVerifyFailed ||
+		e.NumericErrorCode == NumericErrorCodeInvalidToken
+}
+
+//IsThrottled indicates whether Epic is throttling the requests being made.
+func (e *EpicError) IsThrottled() bool {
+	return e.ErrorCode == ErrorCodeThrottled
+}
+
+//UpstreamError is returned when one of Epic's services responds with a non-2xx status code. It
+//carries the HTTP status and raw body along with the EpicError decoded from the body, whose fields
+//and helpers are promoted onto UpstreamError. When the body was an Epic error envelope, the
+//EpicError can also be retrieved with errors.As.
+type UpstreamError struct {
+	StatusCode int
+	Body       []byte
+
+	//RetryAfter is how long Epic asked the client to wait before trying again, taken from the
+	//Retry-After header or a throttling error. It is zero when Epic did not say.
+	RetryAfter time.Duration
+
+	EpicError
+}
+
+func (e *UpstreamError) Error() string {
+	if e.ErrorCode != "" {
+		return fmt.Sprintf("fortnite: upstream returned %v: %v: %v", e.StatusCode, e.ErrorCode, e.ErrorMessage)
+	}
+
+	return fmt.Sprintf("fortnite: upstream returned %v %v", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+//Unwrap exposes the EpicError decoded from the response body. It returns nil when the body was not
+//an Epic error envelope, so errors.As only finds an EpicError Epic actually sent.
+func (e *UpstreamError) Unwrap() error {
+	if e.ErrorCode == "" {
+		return nil
+	}
+
+	return &e.EpicError
+}
+
+//Is allows an UpstreamError to be matched against ErrUpstream as well as the more specific
+//ErrAuthFailed, ErrNotFound and ErrRateLimited depending on the status code and error code received.
+func (e *UpstreamError) Is(target error) bool {
+	switch target {
+	case ErrUpstream:
+		return true
+	case ErrAuthFailed:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden || e.IsInvalidGrant()
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.IsAccountNotFound()
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.IsThrottled()
+	}
+
+	return false
+}
+
+//newUpstreamError builds an UpstreamError from the status code, headers and body of a failed
+//response. Bodies that are not an Epic error envelope leave the EpicError zero-valued.
+func newUpstreamError(statusCode int, header http.Header, body []byte) *UpstreamError {
+	e := &UpstreamError{StatusCode: statusCode, Body: body}
+
+	json.Unmarshal(body, &e.EpicError)
+
+	e.RetryAfter = retryAfter(header, e.EpicError)
+
+	return e
+}
+
+//TwoFactorRequired is returned by Login when the account has two-factor authentication enabled.
+//Pass it to CompleteTwoFactor along with the code the user received to finish logging in. It
+//matches ErrAuthFailed when compared with errors.Is.
+type TwoFactorRequired struct {
+	//Challenge identifies the login attempt the code is being submitted for.
+	Challenge string
+
+	//Method is how the code is delivered, such as "email" or "authenticator".
+	Method string
+
+	err *UpstreamError
+}
+
+func (e *TwoFactorRequired) Error() string {
+	return fmt.Sprintf("fortnite: two-factor authentication required (%v)", e.Method)
+}
+
+//Is allows a TwoFactorRequired to be matched against ErrAuthFailed.
+func (e *TwoFactorRequired) Is(target error) bool {
+	return target == ErrAuthFailed
+}
+
+//Unwrap exposes the UpstreamError Epic responded with.
+func (e *TwoFactorRequired) Unwrap() error {
+	return e.err
+}
+
+//newTwoFactorRequired returns a TwoFactorRequired if err is Epic asking for a two-factor code, and
+//nil otherwise.
+func newTwoFactorRequired(err error) *TwoFactorRequired {
+	var upstream *UpstreamError
+
+	if !errors.As(err, &upstream) || upstream.ErrorCode != ErrorCodeTwoFactorRequired {
+		return nil
+	}
+
+	var body struct {
+		Challenge string `json:"challenge"`
+		Metadata  struct {
+			TwoFactorMethod string `json:"twoFactorMethod"`
+		} `json:"metadata"`
+	}
+
+	json.Unmarshal(upstream.Body, &body)
+
+	return &TwoFactorRequired{
+		Challenge: body.Challenge,
+		Method:    body.Metadata.TwoFactorMethod,
+		err:       upstream,
+	}
+}
@@ -1,13 +1,16 @@
 package fortnite
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/parnurzeal/gorequest"
 )
 
 //OauthTokenRequest holds the required fields to initiate the authentication process
@@ -40,12 +43,22 @@ type OauthTokenExchangeRequest struct {
 	TokenType    string `json:"token_type"`
 }
 
+//OauthRefreshTokenRequest is used to marshal a JSON payload to exchange a refresh token for a new
+//access token.
+type OauthRefreshTokenRequest struct {
+	GrantType    string `json:"grant_type"`
+	RefreshToken string `json:"refresh_token"`
+	IncludePerms bool   `json:"includePerms"`
+}
+
 //OauthTokenResponse is used to unmarshal the JSON response received after successfully
 //completing the authentication process.
 type OauthTokenResponse struct {
-	ExpiresAt    string `json:"expires_at"`
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	ExpiresAt        string `json:"expires_at"`
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
+	AccountID        string `json:"account_id"`
 }
 
 //User represents the state of the user retrieved from the Fortnite API
@@ -670,99 +683,69 @@ type PveInfoResponse struct {
 
 //RawBRStatsResponse is used the unmarshal the JSON response received after successfully
 //querying the leaderboard endpoint of their API.
-type RawBRStatsResponse []struct {
+type RawBRStatsResponse []RawBRStat
+
+//RawBRStat is a single named stat from a RawBRStatsResponse.
+type RawBRStat struct {
 	Name      string  `json:"name"`
 	Value     float64 `json:"value"`
 	Window    string  `json:"window"`
 	OwnerType int     `json:"ownerType"`
 }
 
+//rawStatsV2Response is used to unmarshal the JSON response of the stats endpoint that supports
+//custom time ranges.
+type rawStatsV2Response struct {
+	AccountID string             `json:"accountId"`
+	StartTime int64              `json:"startTime"`
+	EndTime   int64              `json:"endTime"`
+	Stats     map[string]float64 `json:"stats"`
+}
+
 //FormattedBRStats is used to store the BR stats from a RawBRStatsResponse after they have been
-//transformed into a more readable and meaningful state
+//transformed into a more readable and meaningful state.
+//Derived ratios are rounded to two decimal places and are zero when they cannot be calculated,
+//such as KdRatio for a player who has never been eliminated. WinPercentage and the TopN rates are
+//percentages between 0 and 100. Deaths and KillsPerDeathExcludingSoloWins only treat solo wins as
+//matches the player survived, since a player can be eliminated and still win as part of a team.
 type FormattedBRStats struct {
 	Group struct {
-		Solo struct {
-			Wins                float64 `json:"wins"`
-			Top3                float64 `json:"top3"`
-			Top5                float64 `json:"top5"`
-			Top6                float64 `json:"top6"`
-			Top10               float64 `json:"top10"`
-			Top12               float64 `json:"top12"`
-			Top25               float64 `json:"top25"`
-			KdRatio             float64 `json:"kd_ratio"`
-			WinPercentage       float64 `json:"win_percentage"`
-			Matches             float64 `json:"matches"`
-			Kills               float64 `json:"kills"`
-			TimePlayed          float64 `json:"time_played"`
-			TimePlayedFormatted string  `json:"time_played_formatted"`
-			KillsPerMatch       float64 `json:"kills_per_match"`
-			KillsPerMin         float64 `json:"kills_per_min"`
-			Score               float64 `json:"score"`
-		}
-		Duo struct {
-			Wins                float64 `json:"wins"`
-			Top3                float64 `json:"top3"`
-			Top5                float64 `json:"top5"`
-			Top6                float64 `json:"top6"`
-			Top10               float64 `json:"top10"`
-			Top12               float64 `json:"top12"`
-			Top25               float64 `json:"top25"`
-			KdRatio             float64 `json:"kd_ratio"`
-			WinPercentage       float64 `json:"win_percentage"`
-			Matches             float64 `json:"matches"`
-			Kills               float64 `json:"kills"`
-			TimePlayed          float64 `json:"time_played"`
-			TimePlayedFormatted string  `json:"time_played_formatted"`
-			KillsPerMatch       float64 `json:"kills_per_match"`
-			KillsPerMin         float64 `json:"kills_per_min"`
-			Score               float64 `json:"score"`
-		}
-		Squad struct {
-			Wins                float64 `json:"wins"`
-			Top3                float64 `json:"top3"`
-			Top5                float64 `json:"top5"`
-			Top6                float64 `json:"top6"`
-			Top10               float64 `json:"top10"`
-			Top12               float64 `json:"top12"`
-			Top25               float64 `json:"top25"`
-			KdRatio             float64 `json:"kd_ratio"`
-			WinPercentage       float64 `json:"win_percentage"`
-			Matches             float64 `json:"matches"`
-			Kills               float64 `json:"kills"`
-			TimePlayed          float64 `json:"time_played"`
-			TimePlayedFormatted string  `json:"time_played_formatted"`
-			KillsPerMatch       float64 `json:"kills_per_match"`
-			KillsPerMin         float64 `json:"kills_per_min"`
-			Score               float64 `json:"score"`
-		}
+		Solo  ModeStats
+		Duo   ModeStats
+		Squad ModeStats
 	}
 	Info struct {
-		AccountID string `json:"account_id"`
-		Username  string `json:"username"`
-		Platform  string `json:"platform"`
-	}
-	LifetimeStats struct {
-		Wins                float64 `json:"wins"`
-		Top3                float64 `json:"top3"`
-		Top5                float64 `json:"top5"`
-		Top6                float64 `json:"top6"`
-		Top10               float64 `json:"top10"`
-		Top12               float64 `json:"top12"`
-		Top25               float64 `json:"top25"`
-		KdRatio             float64 `json:"kd_ratio"`
-		WinPercentage       float64 `json:"win_percentage"`
-		Matches             float64 `json:"matches"`
-		Kills               float64 `json:"kills"`
-		TimePlayed          float64 `json:"time_played"`
-		TimePlayedFormatted string  `json:"time_played_formatted"`
-		KillsPerMatch       float64 `json:"kills_per_match"`
-		KillsPerMin         float64 `json:"kills_per_min"`
-		Score               float64 `json:"score"`
+		AccountID string      `json:"account_id"`
+		Username  string      `json:"username"`
+		Platform  Platform    `json:"platform"`
+		Window    StatsWindow `json:"window"`
 	}
+
+	//Modes holds the stats of every mode played. It always includes solo, duo and squad, which are
+	//also available in Group, along with a Mode for every unrecognised playlist.
+	Modes map[Mode]ModeStats `json:"modes"`
+
+	//Playlists holds the stats of every playlist played, keyed by playlist name. Only playlists
+	//with a recognised Mode are counted in Group and LifetimeStats.
+	Playlists map[string]PlaylistStats `json:"playlists"`
+
+	//UnknownPlaylists lists the playlists that are not grouped under a Mode.
+	UnknownPlaylists []string `json:"unknown_playlists,omitempty"`
+
+	//LifetimeStats is the Totals of the solo, duo and squad stats.
+	LifetimeStats ModeStats
+}
+
+//BRStatsByPlatform holds a player's Battle Royale stats broken down by platform. Combined adds
+//together the stats of every platform and has an empty Info.Platform.
+type BRStatsByPlatform struct {
+	Platforms map[Platform]FormattedBRStats `json:"platforms"`
+	Combined  FormattedBRStats              `json:"combined"`
 }
 
 //Client represents the Fortnite Client and is used as the access point to query any of the API
-//endpoints.
+//endpoints. A Client is safe for concurrent use; requests run in parallel and only the token
+//fields are guarded, so read them through Tokens while requests may be in flight.
 type Client struct {
 	Email                string
 	Password             string
@@ -771,26 +754,112 @@ type Client struct {
 	AccessToken          string
 	AccessTokenExpiresAt time.Time
 	RefreshToken         string
-	Request              *gorequest.SuperAgent
-	Mutex                sync.Mutex
+
+	//RefreshTokenExpiresAt is when RefreshToken stops being accepted. Once it has passed the
+	//client falls back to a full Login.
+	RefreshTokenExpiresAt time.Time
+
+	//AccountID is the Epic account the tokens belong to.
+	AccountID string
+
+	//DeviceAuth, when set, is used instead of Email and Password whenever the client has to log
+	//in again. LoginWithDeviceAuth sets it.
+	DeviceAuth *DeviceAuth
+
+	//TokenStore, when set, is used to resume a saved session instead of logging in and is kept
+	//up to date whenever the tokens change.
+	TokenStore TokenStore
+
+	//TokenRefreshSkew is how long before AccessTokenExpiresAt the access token is refreshed.
+	TokenRefreshSkew time.Duration
+
+	//Endpoints holds the base URLs of the Epic services this client talks to.
+	Endpoints Endpoints
+
+	//HTTPClient is used to send every request. Its Transport can be replaced to customise
+	//connection pooling or to route requests through a RoundTripper of your own.
+	HTTPClient *http.Client
+
+	//UserAgent is sent as the User-Agent header of every request.
+	UserAgent string
+
+	//Logger receives the client's diagnostics, including a trace of every request with its
+	//method, URL, status and latency. Credentials are redacted. A nil Logger discards them.
+	Logger *slog.Logger
+
+	//RetryPolicy controls how idempotent requests are retried after a transient failure.
+	RetryPolicy RetryPolicy
+
+	//Metrics, when set, receives the endpoint, status code and latency of every request.
+	Metrics Metrics
+
+	//Tracer, when set, starts a span around every call.
+	Tracer Tracer
+
+	//Middleware wraps every HTTP request the client sends. See WithMiddleware.
+	Middleware []Middleware
+
+	//OuterMiddleware wraps every call outside the client's own layers. See WithOuterMiddleware.
+	OuterMiddleware []Middleware
+
+	limiters   map[Service]*rateLimiter
+	mu         sync.RWMutex
+	refreshing *tokenRefresh
+	timeout    time.Duration
 }
 
 //NewClient instantiates an instance of Client that can then be used to make queries to the Fortnite
-//API.
-func NewClient(email string, password string, clientLauncherToken string, fortniteClientToken string) *Client {
+//API. It is configured with Options, for example:
+//
+//	fortnite.NewClient(
+//		fortnite.WithCredentials("email address", "password"),
+//		fortnite.WithClientTokens("client launcher token", "fortnite client token"),
+//		fortnite.WithTimeout(10*time.Second),
+//	)
+func NewClient(opts ...Option) *Client {
 	c := &Client{
-		Email:               email,
-		Password:            password,
-		ClientLauncherToken: clientLauncherToken,
-		FortniteClientToken: fortniteClientToken,
-		Request:             gorequest.New(),
+		TokenRefreshSkew: DefaultTokenRefreshSkew,
+		Endpoints:        DefaultEndpoints(),
+		HTTPClient:       &http.Client{},
+		UserAgent:        DefaultUserAgent,
+		RetryPolicy:      DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout > 0 {
+		httpClient := http.Client{}
+
+		if c.HTTPClient != nil {
+			httpClient = *c.HTTPClient
+		}
+
+		httpClient.Timeout = c.timeout
+		c.HTTPClient = &httpClient
 	}
 
 	return c
 }
 
+//Tokens returns the current access token, its expiry and the refresh token.
+func (c *Client) Tokens() (accessToken string, expiresAt time.Time, refreshToken string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.AccessToken, c.AccessTokenExpiresAt, c.RefreshToken
+}
+
+//endpoints returns the client's Endpoints with any unset base URL filled from DefaultEndpoints.
+func (c *Client) endpoints() Endpoints {
+	return c.Endpoints.withDefaults()
+}
+
 //Login completes the OAuth authentication process, which is required to make calls to the Fortnite API
-func (c *Client) Login() {
+//If the account has two-factor authentication enabled a *TwoFactorRequired error is returned, and
+//the login is finished by passing it to CompleteTwoFactor along with the code sent to the user.
+func (c *Client) Login(ctx context.Context) error {
 	tokenConfig := OauthTokenRequest{
 		GrantType:    "password",
 		Username:     c.Email,
@@ -800,24 +869,45 @@ func (c *Client) Login() {
 
 	var accessTokenResponse OauthTokenRequestResponse
 
-	_, _, err := c.Request.Post(oauthTokenEndpoint).
-		SendStruct(tokenConfig).
-		Type("form").
-		Set("Authorization", fmt.Sprintf("basic %v", c.FortniteClientToken)).
-		EndStruct(&accessTokenResponse)
+	err := c.send(ctx, request{
+		method:   http.MethodPost,
+		url:      c.endpoints().oauthToken(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {basicAuth(c.FortniteClientToken)}},
+		form:     tokenConfig,
+	}, &accessTokenResponse)
+
+	if challenge := newTwoFactorRequired(err); challenge != nil {
+		return challenge
+	}
 
 	if err != nil {
-		log.Fatal("Error with login request 1:", err)
+		return fmt.Errorf("fortnite: login request 1: %w", err)
+	}
+
+	if accessTokenResponse.AccessToken == "" {
+		return fmt.Errorf("fortnite: login request 1: %w", ErrAuthFailed)
 	}
 
+	return c.exchangeLogin(ctx, accessTokenResponse.AccessToken)
+}
+
+//exchangeLogin finishes logging in by trading the access token from the first step of the login
+//for an exchange code, and that exchange code for the session the client uses.
+func (c *Client) exchangeLogin(ctx context.Context, accessToken string) error {
 	var codeResponse OauthRequestCodeResponse
 
-	_, _, err = c.Request.Get(oauthExchangeEndpoint).
-		Set("Authorization", fmt.Sprintf("bearer %v", accessTokenResponse.AccessToken)).
-		EndStruct(&codeResponse)
+	err := c.send(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().oauthExchange(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {fmt.Sprintf("bearer %v", accessToken)}},
+	}, &codeResponse)
 
 	if err != nil {
-		log.Fatal("Error with login request 2:", err)
+		return fmt.Errorf("fortnite: login request 2: %w", err)
 	}
 
 	exchangeRequest := OauthTokenExchangeRequest{
@@ -827,107 +917,102 @@ func (c *Client) Login() {
 		TokenType:    "egl",
 	}
 
-	var tokenResponse OauthTokenResponse
-
-	_, _, err = c.Request.Post(oauthTokenEndpoint).
-		SendStruct(exchangeRequest).
-		Type("form").
-		Set("Authorization", fmt.Sprintf("basic %v", c.FortniteClientToken)).
-		EndStruct(&tokenResponse)
-
-	if err != nil {
-		log.Fatal("Error with login request 3:", err)
+	if err := c.grant(ctx, exchangeRequest); err != nil {
+		return fmt.Errorf("fortnite: login request 3: %w", err)
 	}
 
-	c.Mutex.Lock()
-	c.AccessTokenExpiresAt, _ = time.Parse(time.RFC3339Nano, tokenResponse.ExpiresAt)
-	c.AccessToken = tokenResponse.AccessToken
-	c.RefreshToken = tokenResponse.RefreshToken
-	c.Mutex.Unlock()
+	return nil
 }
 
 //Lookup returns an instance of User which is the information received from the Fortnite API.
-func (c *Client) Lookup(username string) User {
+//ErrNotFound is returned if there is no account with the given username.
+func (c *Client) Lookup(ctx context.Context, username string) (User, error) {
 	var response User
 
-	c.Mutex.Lock()
-	_, _, err := c.Request.Get(lookupURLEndpoint(username)).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().lookup(username),
+		service:  ServicePersona,
+		endpoint: EndpointLookup,
+	}, &response)
 
 	if err != nil {
-		log.Fatal(err)
+		return User{}, err
 	}
 
-	return response
+	if response.ID == "" {
+		return User{}, ErrNotFound
+	}
+
+	return response, nil
 }
 
 //CheckPlayer indicates whether a requested player exists and has played on the requested
-//platform.
-func (c *Client) CheckPlayer(username string, platform string) bool {
+//platform. It returns ErrInvalidPlatform if the platform is not valid.
+func (c *Client) CheckPlayer(ctx context.Context, username string, platform Platform) (bool, error) {
 
-	if !(platform == "pc" || platform == "ps4" || platform == "xb1") {
-		fmt.Println("Bad platform provided;", platform)
-		return false
+	if err := platform.validate(); err != nil {
+		return false, err
 	}
 
-	account := c.Lookup(username)
+	account, err := c.Lookup(ctx, username)
 
-	if account.ID == "" {
-		return false
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
 	}
 
-	var response RawBRStatsResponse
+	if err != nil {
+		return false, err
+	}
+
+	response, err := c.rawStatsBR(ctx, account.ID, StatsWindowAllTime)
 
-	c.Mutex.Lock()
-	c.Request.Get(statsBattleRoyaleEndpoint(account.ID)).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	if err != nil {
+		return false, err
+	}
 
 	for _, stat := range response {
-		if strings.Contains(stat.Name, fmt.Sprintf("_%v_", platform)) {
-			return true
+		if key, ok := ParseStatKey(stat.Name); ok && key.Platform == platform {
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
 }
 
 //GetStatsBR performs the necessary lookups and transformation to return a meaningful
 //representation of your current Battle Royale stats.
-//It will return the stats for the requested platform; useful if the player is active on
-//more than one platform.
-func (c *Client) GetStatsBR(username string, platform string) FormattedBRStats {
+//It will return the stats for the requested platform and window; useful if the player is active
+//on more than one platform. It returns ErrInvalidPlatform if the platform is not valid.
+func (c *Client) GetStatsBR(ctx context.Context, username string, platform Platform, window StatsWindow) (FormattedBRStats, error) {
 
-	if !(platform == "pc" || platform == "ps4" || platform == "xb1") {
-		fmt.Println("Bad platform provided;", platform)
-		return FormattedBRStats{}
+	if err := platform.validate(); err != nil {
+		return FormattedBRStats{}, err
 	}
 
-	account := c.Lookup(username)
+	account, err := c.Lookup(ctx, username)
 
-	var response RawBRStatsResponse
+	if err != nil {
+		return FormattedBRStats{}, err
+	}
 
-	c.Mutex.Lock()
-	c.Request.Get(statsBattleRoyaleEndpoint(account.ID)).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	response, err := c.rawStatsBR(ctx, account.ID, window)
 
-	return processBRStats(response, account, platform)
+	if err != nil {
+		return FormattedBRStats{}, err
+	}
+
+	return processBRStats(response, account, platform, window), nil
 }
 
 //GetStatsBRFromID is an alternative to GetStatsBR through which you can retrieve the stats
 //for an account where you already know the Epic/Fortnite Account ID.
-//It will return the stats for the requested platform; useful if the player is active on
-//more than one platform.
-func (c *Client) GetStatsBRFromID(accountID string, platform string) FormattedBRStats {
+//It will return the stats for the requested platform and window; useful if the player is active
+//on more than one platform. It returns ErrInvalidPlatform if the platform is not valid.
+func (c *Client) GetStatsBRFromID(ctx context.Context, accountID string, platform Platform, window StatsWindow) (FormattedBRStats, error) {
 
-	if !(platform == "pc" || platform == "ps4" || platform == "xb1") {
-		fmt.Println("Bad platform provided;", platform)
-		return FormattedBRStats{}
+	if err := platform.validate(); err != nil {
+		return FormattedBRStats{}, err
 	}
 
 	account := User{
@@ -935,156 +1020,355 @@ func (c *Client) GetStatsBRFromID(accountID string, platform string) FormattedBR
 		DisplayName: "No Username",
 	}
 
-	var response RawBRStatsResponse
+	response, err := c.rawStatsBR(ctx, accountID, window)
+
+	if err != nil {
+		return FormattedBRStats{}, err
+	}
+
+	return processBRStats(response, account, platform, window), nil
+}
+
+//GetStatsBRAllPlatforms performs the same lookups as GetStatsBR but returns the stats for every
+//platform the player has played on during the window, along with their combined totals, from a
+//single request.
+func (c *Client) GetStatsBRAllPlatforms(ctx context.Context, username string, window StatsWindow) (BRStatsByPlatform, error) {
+
+	account, err := c.Lookup(ctx, username)
+
+	if err != nil {
+		return BRStatsByPlatform{}, err
+	}
+
+	response, err := c.rawStatsBR(ctx, account.ID, window)
 
-	c.Mutex.Lock()
-	c.Request.Get(statsBattleRoyaleEndpoint(accountID)).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	if err != nil {
+		return BRStatsByPlatform{}, err
+	}
 
-	return processBRStats(response, account, platform)
+	return processBRStatsByPlatform(response, account, window), nil
 }
 
-//GetFortniteNews returns a variety of news messages displayed in Fortnite.
-//It includes news for Survival, STW, BR, and Login.
-func (c *Client) GetFortniteNews(lang string) NewsResponse {
-	languageHeader := ""
-
-	switch lang {
-	case "fr":
-		languageHeader = "fr-FR"
-	case "en":
-		languageHeader = "en"
-	default:
-		languageHeader = "en"
+//GetStatsBRAllPlatformsFromID is an alternative to GetStatsBRAllPlatforms for an account where you
+//already know the Epic/Fortnite Account ID.
+func (c *Client) GetStatsBRAllPlatformsFromID(ctx context.Context, accountID string, window StatsWindow) (BRStatsByPlatform, error) {
+
+	account := User{
+		ID:          accountID,
+		DisplayName: "No Username",
+	}
+
+	response, err := c.rawStatsBR(ctx, accountID, window)
+
+	if err != nil {
+		return BRStatsByPlatform{}, err
+	}
+
+	return processBRStatsByPlatform(response, account, window), nil
+}
+
+//rawStatsBR fetches the unprocessed Battle Royale stats of an account for the window. Named windows
+//come from the bulk stats endpoint, while custom windows are requested from the stats endpoint that
+//accepts a time range and converted to the same shape.
+func (c *Client) rawStatsBR(ctx context.Context, accountID string, window StatsWindow) (RawBRStatsResponse, error) {
+	if err := window.validate(); err != nil {
+		return nil, err
+	}
+
+	if !window.IsCustom() {
+		var response RawBRStatsResponse
+
+		err := c.sendAuthorized(ctx, request{
+			method:   http.MethodGet,
+			url:      c.endpoints().statsBattleRoyale(accountID, window.named()),
+			service:  ServiceFortnite,
+			endpoint: EndpointStats,
+		}, &response)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return response, nil
 	}
 
+	var response rawStatsV2Response
+
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().statsV2(accountID, window.Start(), window.End()),
+		service:  ServiceFortnite,
+		endpoint: EndpointStats,
+	}, &response)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(RawBRStatsResponse, 0, len(response.Stats))
+
+	for name, value := range response.Stats {
+		stats = append(stats, RawBRStat{Name: name, Value: value, Window: window.String()})
+	}
+
+	return stats, nil
+}
+
+//GetFortniteNews returns a variety of news messages displayed in Fortnite.
+//It includes news for Survival, STW, BR, and Login.
+func (c *Client) GetFortniteNews(ctx context.Context, lang string) (NewsResponse, error) {
 	var response NewsResponse
 
-	c.Mutex.Lock()
-	c.Request.Get(fortniteNewsEndpoint).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		Set("Accept-Language", languageHeader).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().fortniteNews(),
+		service:  ServiceContent,
+		endpoint: EndpointNews,
+		header:   http.Header{"Accept-Language": {languageHeader(lang)}},
+	}, &response)
+
+	if err != nil {
+		return NewsResponse{}, err
+	}
 
-	return response
+	return response, nil
 }
 
 //CheckFortniteStatus checks their status endpoint and will return a bool to
 //indicate whether Fortnite is up or not and if not then the message Fortnite
 //have provided for why it is down.
-func (c *Client) CheckFortniteStatus() (bool, string) {
+func (c *Client) CheckFortniteStatus(ctx context.Context) (bool, string, error) {
 	var response StatusResponse
 
-	c.Mutex.Lock()
-	c.Request.Get(fortniteStatusEndpoint).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().fortniteStatus(),
+		service:  ServiceLightswitch,
+		endpoint: EndpointStatus,
+	}, &response)
+
+	if err != nil {
+		return false, "", err
+	}
 
 	if len(response) > 0 {
 		if response[0].Status == "UP" {
-			return true, ""
+			return true, "", nil
 		}
 
-		return false, response[0].Message
+		return false, response[0].Message, nil
 	}
 
-	return false, "No data returned from status endpoint"
+	return false, "No data returned from status endpoint", nil
 }
 
 //GetFortnitePVEInfo returns a variety of information specific to PVE.
-func (c *Client) GetFortnitePVEInfo(lang string) PveInfoResponse {
-	languageHeader := ""
-
-	switch lang {
-	case "fr":
-		languageHeader = "fr-FR"
-	case "en":
-		languageHeader = "en"
-	default:
-		languageHeader = "en"
-	}
-
+func (c *Client) GetFortnitePVEInfo(ctx context.Context, lang string) (PveInfoResponse, error) {
 	var response PveInfoResponse
 
-	c.Mutex.Lock()
-	c.Request.Get(fortnitePVEInfoEndpoint).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		Set("X-EpicGames-Language", languageHeader).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().fortnitePVEInfo(),
+		service:  ServiceFortnite,
+		endpoint: EndpointPVE,
+		header:   http.Header{"X-EpicGames-Language": {languageHeader(lang)}},
+	}, &response)
 
-	return response
+	if err != nil {
+		return PveInfoResponse{}, err
+	}
+
+	return response, nil
 }
 
 //GetStore returns all the items currently available for purchase for the user.
 //This matches what you would see in the shop within Fortnite.
-func (c *Client) GetStore(lang string) StoreResponse {
-	languageHeader := ""
-
-	switch lang {
-	case "fr":
-		languageHeader = "fr-FR"
-	case "en":
-		languageHeader = "en"
-	default:
-		languageHeader = "en"
-	}
-
+func (c *Client) GetStore(ctx context.Context, lang string) (StoreResponse, error) {
 	var response StoreResponse
 
-	c.Mutex.Lock()
-	c.Request.Get(fortniteStoreEndpoint).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		Set("X-EpicGames-Language", languageHeader).
-		EndStruct(&response)
-	c.Mutex.Unlock()
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().fortniteStore(),
+		service:  ServiceFortnite,
+		endpoint: EndpointStore,
+		header:   http.Header{"X-EpicGames-Language": {languageHeader(lang)}},
+	}, &response)
 
-	return response
+	if err != nil {
+		return StoreResponse{}, err
+	}
+
+	return response, nil
 }
 
 //KillSession is responsible for invalidating your OAuth Token.
-func (c *Client) KillSession() {
-	c.Mutex.Lock()
-	c.Request.Delete(killSessionEndpoint(c.AccessToken)).
-		Set("Authorization", fmt.Sprintf("bearer %v", c.AccessToken)).
-		End()
-
+//The stored tokens are cleared even if Epic could not be reached.
+func (c *Client) KillSession(ctx context.Context) error {
+	accessToken, _, _ := c.Tokens()
+
+	err := c.send(ctx, request{
+		method:   http.MethodDelete,
+		url:      c.endpoints().killSession(accessToken),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {fmt.Sprintf("bearer %v", accessToken)}},
+	}, nil)
+
+	c.mu.Lock()
 	c.AccessToken = ""
 	c.RefreshToken = ""
 	c.AccessTokenExpiresAt = time.Now()
-	c.Mutex.Unlock()
+	c.RefreshTokenExpiresAt = time.Now()
+	c.mu.Unlock()
+
+	if saveErr := c.saveSession(ctx); err == nil {
+		err = saveErr
+	}
+
+	return err
+}
+
+//CheckToken will check whether the current OAuth access token has expired or is within
+//TokenRefreshSkew of expiring, and if it has then it will refresh the token. Authenticated
+//calls already do this automatically, so CheckToken is only needed to refresh ahead of time.
+func (c *Client) CheckToken(ctx context.Context) error {
+	accessToken, _, _ := c.Tokens()
+
+	if accessToken == "" {
+		return nil
+	}
+
+	_, err := c.accessToken(ctx)
+
+	return err
 }
 
-//CheckToken will check whether the current OAuth access token has expired, and
-//if it has then it will refresh the token.
-func (c *Client) CheckToken() {
-	if c.AccessToken == "" {
-		return
+//setTokens stores the tokens from a successful OAuth token response and saves them to the
+//TokenStore.
+func (c *Client) setTokens(ctx context.Context, response OauthTokenResponse) error {
+	expiresAt, _ := time.Parse(time.RFC3339Nano, response.ExpiresAt)
+	refreshExpiresAt, _ := time.Parse(time.RFC3339Nano, response.RefreshExpiresAt)
+
+	c.mu.Lock()
+	c.AccessTokenExpiresAt = expiresAt
+	c.AccessToken = response.AccessToken
+	c.RefreshToken = response.RefreshToken
+	c.RefreshTokenExpiresAt = refreshExpiresAt
+
+	if response.AccountID != "" {
+		c.AccountID = response.AccountID
 	}
+	c.mu.Unlock()
+
+	return c.saveSession(ctx)
+}
+
+//basicAuth returns the Authorization header value for one of the client tokens.
+func basicAuth(clientToken string) string {
+	return fmt.Sprintf("basic %v", clientToken)
+}
+
+//request describes a single call to one of Epic's services. The service decides which rate limit
+//the call counts against, and the endpoint names it in metrics and traces. Authenticated requests
+//are sent with the client's access token. When form is set it is encoded as an
+//application/x-www-form-urlencoded body.
+type request struct {
+	method        string
+	url           string
+	service       Service
+	endpoint      string
+	authenticated bool
+	header        http.Header
+	form          interface{}
+}
 
-	if time.Now().After(c.AccessTokenExpiresAt) {
-		var response OauthTokenResponse
+//build turns the request into an *http.Request bound to ctx, carrying its RequestInfo so that
+//every layer of the middleware chain can see which endpoint it is for.
+func (r request) build(ctx context.Context, userAgent string) (*http.Request, error) {
+	var body io.Reader
 
-		_, _, err := c.Request.Post(oauthTokenEndpoint).
-			Type("multipart").
-			Send(`{"grant_type": "refresh_token"}`).
-			Send(fmt.Sprintf(`{"refresh_token": %v}`, c.RefreshToken)).
-			Send(`{"includePerms": true}`).
-			Set("Authorization", fmt.Sprintf("basic %v", c.ClientLauncherToken)).
-			EndStruct(&response)
+	if r.form != nil {
+		form, err := encodeForm(r.form)
 
 		if err != nil {
-			log.Fatal("Error with check token request:", err)
+			return nil, fmt.Errorf("fortnite: unable to encode request: %w", err)
 		}
 
-		c.Mutex.Lock()
-		c.AccessTokenExpiresAt, _ = time.Parse(time.RFC3339Nano, response.ExpiresAt)
-		c.AccessToken = response.AccessToken
-		c.RefreshToken = response.RefreshToken
-		c.Mutex.Unlock()
+		body = strings.NewReader(form.Encode())
+	}
+
+	ctx = context.WithValue(ctx, requestInfoKey{}, RequestInfo{
+		Service:       r.service,
+		Endpoint:      r.endpoint,
+		Authenticated: r.authenticated,
+	})
+
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("fortnite: unable to build request: %w", err)
+	}
+
+	for key, values := range r.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if r.form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+
+	return req, nil
+}
+
+//send performs the request through the client's middleware chain and unmarshals a successful
+//response into v. The request is bound to ctx so that cancellation and deadlines abort the
+//underlying HTTP call. Transport failures, non-2xx responses and bodies that cannot be decoded
+//are all returned as errors.
+func (c *Client) send(ctx context.Context, r request, v interface{}) error {
+	req, err := r.build(ctx, c.UserAgent)
+
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doer().Do(req)
+
+	if err != nil {
+		return err
 	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return fmt.Errorf("fortnite: unable to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newUpstreamError(resp.StatusCode, resp.Header, respBody)
+	}
+
+	if v == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("fortnite: unable to decode response: %w", err)
+	}
+
+	return nil
+}
+
+//sendAuthorized performs a request that requires the access token.
+func (c *Client) sendAuthorized(ctx context.Context, r request, v interface{}) error {
+	r.authenticated = true
+
+	return c.send(ctx, r, v)
 }
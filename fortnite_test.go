@@ -0,0 +1,344 @@
+package fortnite
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+//newTestClient returns a Client whose every endpoint points at a stub server running handler.
+func newTestClient(t testing.TB, handler http.HandlerFunc, opts ...Option) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]Option{WithEndpoints(Endpoints{
+		Account:     server.URL,
+		Persona:     server.URL,
+		Lightswitch: server.URL,
+		Content:     server.URL,
+		Fortnite:    server.URL,
+	})}, opts...)
+
+	return NewClient(opts...)
+}
+
+//tokenResponse is the body of a successful OAuth token grant for accessToken.
+func tokenResponse(accessToken string) string {
+	return fmt.Sprintf(`{"access_token":%q,"expires_at":"2099-01-01T00:00:00Z","refresh_token":"refresh-%v","refresh_expires_at":"2099-01-01T00:00:00Z","account_id":"account-id"}`,
+		accessToken, accessToken)
+}
+
+//BenchmarkGetStatsBRFromIDParallel measures the throughput of concurrent GetStatsBRFromID calls
+//against a local stub server that takes a millisecond to answer each one.
+func BenchmarkGetStatsBRFromIDParallel(b *testing.B) {
+	client := newTestClient(b, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"name":"br_placetop1_pc_m0_p2","value":3},
+			{"name":"br_matchesplayed_pc_m0_p2","value":40},
+			{"name":"br_kills_pc_m0_p2","value":55},
+			{"name":"br_minutesplayed_pc_m0_p2","value":300}
+		]`))
+	})
+
+	client.AccessToken = "access-token"
+	client.AccessTokenExpiresAt = time.Now().Add(time.Hour)
+
+	ctx := context.Background()
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.GetStatsBRFromID(ctx, "account-id", PlatformPC, StatsWindowAllTime); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func TestConcurrentCallersShareOneRefresh(t *testing.T) {
+	var refreshes int32
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account/api/oauth/token" {
+			r.ParseForm()
+
+			if grant := r.PostForm.Get("grant_type"); grant != "refresh_token" {
+				t.Errorf("grant_type = %q, want refresh_token", grant)
+			}
+
+			atomic.AddInt32(&refreshes, 1)
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte(tokenResponse("new")))
+			return
+		}
+
+		if got := r.Header.Get("Authorization"); got != "bearer new" {
+			t.Errorf("Authorization = %q, want bearer new", got)
+		}
+
+		w.Write([]byte(`{"id":"id","displayName":"name"}`))
+	})
+
+	client.AccessToken = "old"
+	client.AccessTokenExpiresAt = time.Now()
+	client.RefreshToken = "refresh-old"
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if _, err := client.Lookup(context.Background(), "name"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if refreshes != 1 {
+		t.Errorf("refresh_token grants = %v, want 1", refreshes)
+	}
+}
+
+func TestCancelledCallerDoesNotFailSharedRefresh(t *testing.T) {
+	refreshStarted := make(chan struct{})
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account/api/oauth/token" {
+			close(refreshStarted)
+			time.Sleep(100 * time.Millisecond)
+			w.Write([]byte(tokenResponse("new")))
+			return
+		}
+
+		w.Write([]byte(`{"id":"id","displayName":"name"}`))
+	})
+
+	client.AccessToken = "old"
+	client.AccessTokenExpiresAt = time.Now()
+	client.RefreshToken = "refresh-old"
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	second := make(chan error, 1)
+
+	go func() {
+		_, err := client.Lookup(firstCtx, "name")
+		first <- err
+	}()
+
+	<-refreshStarted
+
+	go func() {
+		_, err := client.Lookup(context.Background(), "name")
+		second <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancelFirst()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller error = %v, want context.Canceled", err)
+	}
+
+	if err := <-second; err != nil {
+		t.Errorf("second caller error = %v, want nil", err)
+	}
+}
+
+func TestRejectedTokenIsRefreshedAndRetriedOnce(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		authHeaders []string
+		refreshes   int32
+	)
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account/api/oauth/token" {
+			atomic.AddInt32(&refreshes, 1)
+			w.Write([]byte(tokenResponse("new")))
+			return
+		}
+
+		mu.Lock()
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		if r.Header.Get("Authorization") != "bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errorCode":"errors.com.epicgames.common.authentication.authentication_failed","numericErrorCode":1014}`))
+			return
+		}
+
+		w.Write([]byte(`{"id":"id","displayName":"name"}`))
+	})
+
+	client.AccessToken = "old"
+	client.AccessTokenExpiresAt = time.Now().Add(time.Hour)
+	client.RefreshToken = "refresh-old"
+
+	if _, err := client.Lookup(context.Background(), "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(authHeaders) != 2 || authHeaders[0] != "bearer old" || authHeaders[1] != "bearer new" {
+		t.Errorf("Authorization headers = %q, want [bearer old bearer new]", authHeaders)
+	}
+
+	if refreshes != 1 {
+		t.Errorf("refresh_token grants = %v, want 1", refreshes)
+	}
+}
+
+func TestInvalidRefreshTokenFallsBackToLogin(t *testing.T) {
+	var grants []string
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/account/api/oauth/token":
+			r.ParseForm()
+			grant := r.PostForm.Get("grant_type")
+			grants = append(grants, grant)
+
+			switch grant {
+			case "refresh_token":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errorCode":"errors.com.epicgames.account.oauth.invalid_grant"}`))
+			case "password":
+				w.Write([]byte(`{"access_token":"login"}`))
+			case "exchange_code":
+				w.Write([]byte(tokenResponse("new")))
+			}
+		case "/account/api/oauth/exchange":
+			w.Write([]byte(`{"code":"exchange-code"}`))
+		default:
+			w.Write([]byte(`{"id":"id","displayName":"name"}`))
+		}
+	}, WithCredentials("email", "password"))
+
+	client.AccessToken = "old"
+	client.AccessTokenExpiresAt = time.Now()
+	client.RefreshToken = "refresh-old"
+	client.RefreshTokenExpiresAt = time.Now().Add(time.Hour)
+
+	if _, err := client.Lookup(context.Background(), "name"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"refresh_token", "password", "exchange_code"}; fmt.Sprint(grants) != fmt.Sprint(want) {
+		t.Errorf("grants = %v, want %v", grants, want)
+	}
+
+	if accessToken, _, _ := client.Tokens(); accessToken != "new" {
+		t.Errorf("access token = %q, want new", accessToken)
+	}
+}
+
+func TestKillSessionSavesEmptySession(t *testing.T) {
+	store := NewMemoryTokenStore()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %v, want DELETE", r.Method)
+		}
+	}, WithTokenStore(store))
+
+	if err := client.setTokens(context.Background(), OauthTokenResponse{AccessToken: "access", RefreshToken: "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.KillSession(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if session, err := store.Load(context.Background()); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Load after KillSession = %+v, %v, want ErrNoSession", session, err)
+	}
+}
+
+func TestOuterMiddlewareRunsOutsideRateLimitAndAuth(t *testing.T) {
+	var requests int32
+	var cached []byte
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name":"br_matchesplayed_pc_m0_p2","value":40}]`))
+	},
+		WithRateLimit(ServiceFortnite, RateLimit{Rate: 0.001, Burst: 1}),
+		WithOuterMiddleware(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if header := req.Header.Get("Authorization"); header != "" {
+					t.Errorf("outer middleware saw Authorization %q, want none", header)
+				}
+
+				if cached == nil {
+					resp, err := next.Do(req)
+
+					if err != nil {
+						return nil, err
+					}
+
+					defer resp.Body.Close()
+
+					if cached, err = io.ReadAll(resp.Body); err != nil {
+						return nil, err
+					}
+				}
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"application/json"}},
+					Body:       io.NopCloser(bytes.NewReader(cached)),
+					Request:    req,
+				}, nil
+			})
+		}),
+		WithMiddleware(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				if header := req.Header.Get("Authorization"); header != "bearer access-token" {
+					t.Errorf("inner middleware saw Authorization %q, want the bearer token", header)
+				}
+
+				return next.Do(req)
+			})
+		}),
+	)
+
+	client.AccessToken = "access-token"
+	client.AccessTokenExpiresAt = time.Now().Add(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		stats, err := client.GetStatsBRFromID(ctx, "account-id", PlatformPC, StatsWindowAllTime)
+
+		if err != nil {
+			t.Fatalf("call %v: %v", i+1, err)
+		}
+
+		if stats.LifetimeStats.Matches != 40 {
+			t.Errorf("call %v: Matches = %v, want 40", i+1, stats.LifetimeStats.Matches)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("server received %v requests, want 1", got)
+	}
+}
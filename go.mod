@@ -0,0 +1,3 @@
+module github.com/jryd/fortnite
+
+go 1.24
@@ -1,174 +1,113 @@
 package fortnite
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
-	"strings"
+	"net/url"
+	"sort"
 )
 
-func processBRStats(stats RawBRStatsResponse, account User, platform string) FormattedBRStats {
-	totalTime := 0.00
-
+//processBRStats formats the stats recorded on the given platform during the window. An empty
+//platform combines the stats of every valid platform, so that it matches the sum of the platforms
+//reported by processBRStatsByPlatform.
+func processBRStats(stats RawBRStatsResponse, account User, platform Platform, window StatsWindow) FormattedBRStats {
 	var results FormattedBRStats
 
-	for _, stat := range stats {
+	results.Playlists = map[string]PlaylistStats{}
 
-		//Solo, Duos, or Squads
-		var mode string
+	for _, stat := range stats {
+		key, ok := ParseStatKey(stat.Name)
 
-		if strings.Contains(stat.Name, "_p2") {
-			mode = "solo"
-		} else if strings.Contains(stat.Name, "_p10") {
-			mode = "duo"
-		} else {
-			mode = "squad"
+		if !ok || !key.Platform.Valid() || (platform != "" && key.Platform != platform) {
+			continue
 		}
 
-		//Which type of result is this element
-		if strings.Contains(stat.Name, fmt.Sprintf("placetop1_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Wins = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Wins = stat.Value
-			} else {
-				results.Group.Squad.Wins = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop3_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top3 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top3 = stat.Value
-			} else {
-				results.Group.Squad.Top3 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop5_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top5 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top5 = stat.Value
-			} else {
-				results.Group.Squad.Top5 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop6_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top6 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top6 = stat.Value
-			} else {
-				results.Group.Squad.Top6 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop10_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top10 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top10 = stat.Value
-			} else {
-				results.Group.Squad.Top10 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop12_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top12 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top12 = stat.Value
-			} else {
-				results.Group.Squad.Top12 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("placetop25_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Top25 = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Top25 = stat.Value
-			} else {
-				results.Group.Squad.Top25 = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("matchesplayed_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Matches = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Matches = stat.Value
-			} else {
-				results.Group.Squad.Matches = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("kills_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Kills = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Kills = stat.Value
-			} else {
-				results.Group.Squad.Kills = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("score_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.Score = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.Score = stat.Value
-			} else {
-				results.Group.Squad.Score = stat.Value
-			}
-		} else if strings.Contains(stat.Name, fmt.Sprintf("minutesplayed_%v", platform)) {
-			if mode == "solo" {
-				results.Group.Solo.TimePlayed = stat.Value
-			} else if mode == "duo" {
-				results.Group.Duo.TimePlayed = stat.Value
-			} else {
-				results.Group.Squad.TimePlayed = stat.Value
-			}
-
-			totalTime += stat.Value
-		}
+		playlist := results.Playlists[key.Playlist]
+		playlist.Mode, _ = PlaylistMode(key.Playlist)
+		playlist.addStat(key.Stat, stat.Value)
+		results.Playlists[key.Playlist] = playlist
 	}
 
-	results.Group.Solo.KdRatio = math.Round(results.Group.Solo.Kills/(results.Group.Solo.Matches-results.Group.Solo.Wins)*100) / 100
-	results.Group.Duo.KdRatio = math.Round(results.Group.Duo.Kills/(results.Group.Duo.Matches-results.Group.Duo.Wins)*100) / 100
-	results.Group.Squad.KdRatio = math.Round(results.Group.Squad.Kills/(results.Group.Squad.Matches-results.Group.Squad.Wins)*100) / 100
-
-	results.Group.Solo.WinPercentage = math.Round((results.Group.Solo.Wins/results.Group.Solo.Matches)*100) / 100
-	results.Group.Duo.WinPercentage = math.Round((results.Group.Duo.Wins/results.Group.Duo.Matches)*100) / 100
-	results.Group.Squad.WinPercentage = math.Round((results.Group.Squad.Wins/results.Group.Squad.Matches)*100) / 100
+	results.Modes = map[Mode]ModeStats{
+		ModeSolo:  Totals(),
+		ModeDuo:   Totals(),
+		ModeSquad: Totals(),
+	}
 
-	results.Group.Solo.KillsPerMin = math.Round(results.Group.Solo.Kills/results.Group.Solo.TimePlayed*100) / 100
-	results.Group.Duo.KillsPerMin = math.Round(results.Group.Duo.Kills/results.Group.Duo.TimePlayed*100) / 100
-	results.Group.Squad.KillsPerMin = math.Round(results.Group.Squad.Kills/results.Group.Squad.TimePlayed*100) / 100
+	for name, playlist := range results.Playlists {
+		if playlist.Mode == ModeSolo {
+			playlist.SoloWins = playlist.Wins
+		}
 
-	results.Group.Solo.TimePlayedFormatted = formatTimeString(results.Group.Solo.TimePlayed)
-	results.Group.Duo.TimePlayedFormatted = formatTimeString(results.Group.Duo.TimePlayed)
-	results.Group.Squad.TimePlayedFormatted = formatTimeString(results.Group.Squad.TimePlayed)
+		playlist.derive()
+		results.Playlists[name] = playlist
 
-	results.Group.Solo.KillsPerMatch = math.Round(results.Group.Solo.Kills/results.Group.Solo.Matches*100) / 100
-	results.Group.Duo.KillsPerMatch = math.Round(results.Group.Duo.Kills/results.Group.Duo.Matches*100) / 100
-	results.Group.Squad.KillsPerMatch = math.Round(results.Group.Squad.Kills/results.Group.Squad.Matches*100) / 100
+		mode := playlist.Mode
 
-	// <------------------------------------------------------------------->
+		if mode == "" {
+			mode = Mode(name)
+			results.UnknownPlaylists = append(results.UnknownPlaylists, name)
+		}
 
-	//Calculate lifetimeStats
-	results.LifetimeStats.Wins = results.Group.Solo.Wins + results.Group.Duo.Wins + results.Group.Squad.Wins
-	results.LifetimeStats.Top3 = results.Group.Solo.Top3 + results.Group.Duo.Top3 + results.Group.Squad.Top3
-	results.LifetimeStats.Top5 = results.Group.Solo.Top5 + results.Group.Duo.Top5 + results.Group.Squad.Top5
-	results.LifetimeStats.Top6 = results.Group.Solo.Top6 + results.Group.Duo.Top6 + results.Group.Squad.Top6
-	results.LifetimeStats.Top10 = results.Group.Solo.Top10 + results.Group.Duo.Top10 + results.Group.Squad.Top10
-	results.LifetimeStats.Top12 = results.Group.Solo.Top12 + results.Group.Duo.Top12 + results.Group.Squad.Top12
-	results.LifetimeStats.Top25 = results.Group.Solo.Top25 + results.Group.Duo.Top25 + results.Group.Squad.Top25
-	results.LifetimeStats.Matches = results.Group.Solo.Matches + results.Group.Duo.Matches + results.Group.Squad.Matches
-	results.LifetimeStats.Kills = results.Group.Solo.Kills + results.Group.Duo.Kills + results.Group.Squad.Kills
-	results.LifetimeStats.Score = results.Group.Solo.Score + results.Group.Duo.Score + results.Group.Squad.Score
+		results.Modes[mode] = results.Modes[mode].Add(playlist.ModeStats)
+	}
 
-	results.LifetimeStats.TimePlayed = totalTime
+	sort.Strings(results.UnknownPlaylists)
 
-	results.LifetimeStats.KdRatio = math.Round(results.LifetimeStats.Kills/(results.LifetimeStats.Matches-results.LifetimeStats.Wins)*100) / 100
-	results.LifetimeStats.WinPercentage = math.Round((results.LifetimeStats.Wins/results.LifetimeStats.Matches)*100) / 100
-	results.LifetimeStats.TimePlayedFormatted = formatTimeString(results.LifetimeStats.TimePlayed)
-	results.LifetimeStats.KillsPerMin = math.Round(results.LifetimeStats.Kills/results.LifetimeStats.TimePlayed*100) / 100
-	results.LifetimeStats.KillsPerMatch = math.Round(results.LifetimeStats.Kills/results.LifetimeStats.Matches*100) / 100
+	results.Group.Solo = results.Modes[ModeSolo]
+	results.Group.Duo = results.Modes[ModeDuo]
+	results.Group.Squad = results.Modes[ModeSquad]
 
-	results.LifetimeStats.Wins = results.Group.Solo.Wins + results.Group.Duo.Wins + results.Group.Squad.Wins
+	results.LifetimeStats = Totals(results.Group.Solo, results.Group.Duo, results.Group.Squad)
 
 	results.Info.AccountID = account.ID
 	results.Info.Username = account.DisplayName
 	results.Info.Platform = platform
+	results.Info.Window = window
+
+	return results
+}
+
+//processBRStatsByPlatform formats the stats of each platform found in the response separately and
+//combined.
+func processBRStatsByPlatform(stats RawBRStatsResponse, account User, window StatsWindow) BRStatsByPlatform {
+	results := BRStatsByPlatform{
+		Platforms: map[Platform]FormattedBRStats{},
+		Combined:  processBRStats(stats, account, "", window),
+	}
+
+	for _, stat := range stats {
+		key, ok := ParseStatKey(stat.Name)
+
+		if !ok || !key.Platform.Valid() {
+			continue
+		}
+
+		if _, done := results.Platforms[key.Platform]; !done {
+			results.Platforms[key.Platform] = processBRStats(stats, account, key.Platform, window)
+		}
+	}
 
 	return results
 }
 
+//ratio divides numerator by denominator, rounded to two decimal places. It is zero rather than NaN
+//or infinite when the denominator is zero, such as for a player with no matches.
+func ratio(numerator float64, denominator float64) float64 {
+	if denominator <= 0 {
+		return 0
+	}
+
+	return math.Round(numerator/denominator*100) / 100
+}
+
+//percentage expresses part as a percentage of whole, rounded to two decimal places. It is zero
+//when whole is zero.
+func percentage(part float64, whole float64) float64 {
+	return ratio(part*100, whole)
+}
+
 func formatTimeString(time float64) string {
 	result := ""
 	days := math.Floor(time / 24 / 60)
@@ -189,3 +128,36 @@ func formatTimeString(time float64) string {
 
 	return result
 }
+
+//languageHeader maps the language codes accepted by the client onto the values Epic expects.
+func languageHeader(lang string) string {
+	switch lang {
+	case "fr":
+		return "fr-FR"
+	default:
+		return "en"
+	}
+}
+
+//encodeForm converts a request struct into form values using the names from its JSON tags.
+func encodeForm(v interface{}) (url.Values, error) {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+
+	for key, value := range fields {
+		form.Set(key, fmt.Sprint(value))
+	}
+
+	return form, nil
+}
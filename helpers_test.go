@@ -0,0 +1,150 @@
+package fortnite
+
+import (
+	"reflect"
+	"testing"
+)
+
+//counts picks out the counters the processBRStats tests check.
+type counts struct {
+	Wins, Matches, Kills, SoloWins float64
+}
+
+func countsOf(s ModeStats) counts {
+	return counts{Wins: s.Wins, Matches: s.Matches, Kills: s.Kills, SoloWins: s.SoloWins}
+}
+
+func TestProcessBRStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform Platform
+		stats    RawBRStatsResponse
+
+		solo, duo, squad, lifetime counts
+		unknown                    []string
+		modes                      map[Mode]counts
+	}{
+		{
+			name:     "old keys",
+			platform: PlatformPC,
+			stats: RawBRStatsResponse{
+				{Name: "br_placetop1_pc_m0_p2", Value: 2},
+				{Name: "br_matchesplayed_pc_m0_p2", Value: 10},
+				{Name: "br_kills_pc_m0_p2", Value: 15},
+				{Name: "br_placetop1_pc_m0_p10", Value: 1},
+				{Name: "br_matchesplayed_pc_m0_p10", Value: 4},
+				{Name: "br_matchesplayed_pc_m0_p9", Value: 6},
+				{Name: "br_kills_pc_m0_p9", Value: 9},
+			},
+			solo:     counts{Wins: 2, Matches: 10, Kills: 15, SoloWins: 2},
+			duo:      counts{Wins: 1, Matches: 4},
+			squad:    counts{Matches: 6, Kills: 9},
+			lifetime: counts{Wins: 3, Matches: 20, Kills: 24, SoloWins: 2},
+		},
+		{
+			name:     "playlist keys",
+			platform: PlatformKeyboardMouse,
+			stats: RawBRStatsResponse{
+				{Name: "br_placetop1_keyboardmouse_m0_playlist_defaultsolo", Value: 1},
+				{Name: "br_matchesplayed_keyboardmouse_m0_playlist_defaultsolo", Value: 5},
+				{Name: "br_matchesplayed_keyboardmouse_m0_playlist_defaultduo", Value: 3},
+				{Name: "br_kills_keyboardmouse_m0_playlist_defaultsquad", Value: 7},
+			},
+			solo:     counts{Wins: 1, Matches: 5, SoloWins: 1},
+			duo:      counts{Matches: 3},
+			squad:    counts{Kills: 7},
+			lifetime: counts{Wins: 1, Matches: 8, Kills: 7, SoloWins: 1},
+		},
+		{
+			name:     "other platforms are left out",
+			platform: PlatformGamepad,
+			stats: RawBRStatsResponse{
+				{Name: "br_matchesplayed_gamepad_m0_playlist_defaultsolo", Value: 2},
+				{Name: "br_matchesplayed_keyboardmouse_m0_playlist_defaultsolo", Value: 5},
+				{Name: "br_matchesplayed_pc_m0_p2", Value: 7},
+			},
+			solo:     counts{Matches: 2},
+			lifetime: counts{Matches: 2},
+		},
+		{
+			name: "every platform when none is given",
+			stats: RawBRStatsResponse{
+				{Name: "br_matchesplayed_gamepad_m0_playlist_defaultsolo", Value: 2},
+				{Name: "br_matchesplayed_keyboardmouse_m0_playlist_defaultsolo", Value: 5},
+				{Name: "br_matchesplayed_pc_m0_p2", Value: 7},
+			},
+			solo:     counts{Matches: 14},
+			lifetime: counts{Matches: 14},
+		},
+		{
+			name: "malformed keys and invalid platforms are skipped",
+			stats: RawBRStatsResponse{
+				{Name: "br_matchesplayed_pc_m0_p2", Value: 7},
+				{Name: "br_matchesplayed_toaster_m0_p2", Value: 100},
+				{Name: "br_matchesplayed_pc_m1_p2", Value: 100},
+				{Name: "br_matchesplayed_pc_m0_playlist", Value: 100},
+				{Name: "stw_matchesplayed_pc_m0_p2", Value: 100},
+				{Name: "nonsense", Value: 100},
+			},
+			solo:     counts{Matches: 7},
+			lifetime: counts{Matches: 7},
+		},
+		{
+			name:     "limited time modes are kept apart",
+			platform: PlatformTouch,
+			stats: RawBRStatsResponse{
+				{Name: "br_matchesplayed_touch_m0_playlist_defaultsolo", Value: 4},
+				{Name: "br_placetop1_touch_m0_playlist_playground_ltm_v2", Value: 1},
+				{Name: "br_matchesplayed_touch_m0_playlist_playground_ltm_v2", Value: 3},
+				{Name: "br_kills_touch_m0_playlist_playground_ltm_v2", Value: 12},
+			},
+			solo:     counts{Matches: 4},
+			lifetime: counts{Matches: 4},
+			unknown:  []string{"playground_ltm_v2"},
+			modes: map[Mode]counts{
+				"playground_ltm_v2": {Wins: 1, Matches: 3, Kills: 12},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			window := StatsWindowAllTime
+			stats := processBRStats(test.stats, User{ID: "account-id", DisplayName: "player"}, test.platform, window)
+
+			if got := countsOf(stats.Group.Solo); got != test.solo {
+				t.Errorf("Group.Solo = %+v, want %+v", got, test.solo)
+			}
+
+			if got := countsOf(stats.Group.Duo); got != test.duo {
+				t.Errorf("Group.Duo = %+v, want %+v", got, test.duo)
+			}
+
+			if got := countsOf(stats.Group.Squad); got != test.squad {
+				t.Errorf("Group.Squad = %+v, want %+v", got, test.squad)
+			}
+
+			if got := countsOf(stats.LifetimeStats); got != test.lifetime {
+				t.Errorf("LifetimeStats = %+v, want %+v", got, test.lifetime)
+			}
+
+			if !reflect.DeepEqual(stats.UnknownPlaylists, test.unknown) {
+				t.Errorf("UnknownPlaylists = %v, want %v", stats.UnknownPlaylists, test.unknown)
+			}
+
+			if len(stats.Modes) != 3+len(test.modes) {
+				t.Errorf("got %v modes, want %v", len(stats.Modes), 3+len(test.modes))
+			}
+
+			for mode, want := range test.modes {
+				if got := countsOf(stats.Modes[mode]); got != want {
+					t.Errorf("Modes[%v] = %+v, want %+v", mode, got, want)
+				}
+			}
+
+			if stats.Info.AccountID != "account-id" || stats.Info.Username != "player" || stats.Info.Platform != test.platform || stats.Info.Window != window {
+				t.Errorf("Info = %+v, want the account, platform and window given", stats.Info)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package fortnite
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+//The endpoint names reported to Metrics and Tracer.
+const (
+	EndpointOAuth      = "oauth"
+	EndpointDeviceAuth = "deviceauth"
+	EndpointLookup     = "lookup"
+	EndpointStats      = "stats"
+	EndpointStore      = "store"
+	EndpointNews       = "news"
+	EndpointStatus     = "status"
+	EndpointPVE        = "pve"
+)
+
+//Metrics receives a measurement for every HTTP request the client sends, including each retry.
+//Implementations typically increment a counter and observe a latency histogram labelled by
+//endpoint and status code. The status code is zero when no response was received.
+type Metrics interface {
+	ObserveRequest(endpoint string, statusCode int, duration time.Duration)
+}
+
+//Tracer starts a span around every call the client makes to an Epic endpoint. The span covers all
+//attempts at the call, and the context it returns is used for the underlying HTTP requests so that
+//transport-level instrumentation nests beneath it. It is shaped so that an OpenTelemetry tracer can
+//be adapted to it in a few lines.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+//Span is a single traced call started by a Tracer.
+type Span interface {
+	//SetAttribute records a key and value on the span.
+	SetAttribute(key string, value interface{})
+
+	//End finishes the span, recording err if the call failed.
+	End(err error)
+}
+
+//WithMetrics sets the Metrics that every request is reported to.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.Metrics = metrics
+	}
+}
+
+//WithTracer sets the Tracer used to start a span around every call.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) {
+		c.Tracer = tracer
+	}
+}
+
+//startSpan starts a span for the request.
+func (c *Client) startSpan(ctx context.Context, req *http.Request, info RequestInfo) (context.Context, Span) {
+	ctx, span := c.Tracer.StartSpan(ctx, "fortnite."+info.Endpoint)
+	span.SetAttribute("fortnite.endpoint", info.Endpoint)
+	span.SetAttribute("fortnite.service", string(info.Service))
+	span.SetAttribute("http.method", req.Method)
+
+	return ctx, span
+}
+
+//observeRequest reports a single HTTP round-trip to the client's Metrics.
+func (c *Client) observeRequest(info RequestInfo, statusCode int, duration time.Duration) {
+	if c.Metrics != nil {
+		c.Metrics.ObserveRequest(info.Endpoint, statusCode, duration)
+	}
+}
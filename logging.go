@@ -0,0 +1,95 @@
+package fortnite
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//discardLogger is used when the client has no Logger, so that nothing is logged by default.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+//logger returns the client's Logger, or one that discards everything if it is unset.
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return discardLogger
+	}
+
+	return c.Logger
+}
+
+//logRequest traces a single HTTP round-trip. Successful requests are logged at debug level and
+//failed ones at warn level, with any credentials removed from the URL and headers.
+func (c *Client) logRequest(req *http.Request, status int, latency time.Duration, err error) {
+	ctx := req.Context()
+	level := slog.LevelDebug
+
+	if err != nil || status < 200 || status > 299 {
+		level = slog.LevelWarn
+	}
+
+	logger := c.logger()
+
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Int("status", status),
+		slog.Duration("latency", latency),
+		slog.Any("headers", redactHeader(req.Header)),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, "fortnite request", attrs...)
+}
+
+//logRetry records that a failed request is about to be retried.
+func (c *Client) logRetry(req *http.Request, attempt int, delay time.Duration, err error) {
+	info, _ := RequestInfoFromContext(req.Context())
+
+	c.logger().LogAttrs(req.Context(), slog.LevelInfo, "fortnite request retrying",
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.String("service", string(info.Service)),
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+		slog.String("error", err.Error()),
+	)
+}
+
+//redactHeader returns a copy of the header with the credentials in the Authorization header
+//replaced, keeping only the scheme.
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+
+	if auth := redacted.Get("Authorization"); auth != "" {
+		scheme, _, _ := strings.Cut(auth, " ")
+		redacted.Set("Authorization", scheme+" REDACTED")
+	}
+
+	return redacted
+}
+
+//redactURL returns the URL as a string with the access token removed from kill session requests,
+//which carry it in the path.
+func redactURL(u *url.URL) string {
+	const killPath = "/sessions/kill/"
+
+	if i := strings.Index(u.Path, killPath); i >= 0 {
+		redacted := *u
+		redacted.Path = u.Path[:i+len(killPath)] + "REDACTED"
+		redacted.RawPath = ""
+
+		return redacted.String()
+	}
+
+	return u.String()
+}
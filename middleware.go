@@ -0,0 +1,287 @@
+package fortnite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+//Doer sends an HTTP request and returns its response. *http.Client satisfies Doer.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+//DoerFunc adapts an ordinary function to a Doer.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+//Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+//Middleware wraps a Doer with another layer of behaviour, such as adding headers, recording
+//traffic or serving canned responses in tests. A middleware may inspect or replace the request
+//before calling next, and the response after.
+type Middleware func(next Doer) Doer
+
+//RequestInfo describes which Epic endpoint a request is for. It is carried in the context of every
+//request the client sends, so middleware can find it with RequestInfoFromContext.
+type RequestInfo struct {
+	Service       Service
+	Endpoint      string
+	Authenticated bool
+}
+
+type requestInfoKey struct{}
+
+//RequestInfoFromContext returns the RequestInfo of a request sent by the client.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+
+	return info, ok
+}
+
+//WithMiddleware adds middleware around every HTTP request the client sends. The client's own
+//layers run in this order, outermost first: tracing, authentication, retries, rate limiting, the
+//middleware given here, then logging and metrics just before the request reaches the HTTPClient.
+//Middleware therefore sees every attempt, with its Authorization header set, and logs and metrics
+//reflect any changes it makes. The first middleware given is the outermost of them. Use
+//WithOuterMiddleware for middleware that should run outside the client's own layers instead.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+//WithOuterMiddleware adds middleware outside all of the client's own layers, so it runs once per
+//call before tracing, authentication, retries and rate limiting. This suits middleware such as a
+//response cache, which can answer a request without spending a rate limit token or logging in.
+//Outer middleware sees the request before its Authorization header is set, and a response it
+//returns without calling next is not retried, logged or measured. The first middleware given is
+//the outermost of them.
+func WithOuterMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.OuterMiddleware = append(c.OuterMiddleware, middleware...)
+	}
+}
+
+//doer assembles the client's middleware chain around its HTTPClient.
+func (c *Client) doer() Doer {
+	var doer Doer = DoerFunc(c.transport)
+
+	doer = c.observeLayer(doer)
+
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		doer = c.Middleware[i](doer)
+	}
+
+	doer = c.rateLimitLayer(doer)
+	doer = c.retryLayer(doer)
+	doer = c.authLayer(doer)
+	doer = c.traceLayer(doer)
+
+	for i := len(c.OuterMiddleware) - 1; i >= 0; i-- {
+		doer = c.OuterMiddleware[i](doer)
+	}
+
+	return doer
+}
+
+//transport sends the request with the client's HTTPClient.
+func (c *Client) transport(req *http.Request) (*http.Response, error) {
+	httpClient := c.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	return resp, nil
+}
+
+//traceLayer starts a span around the whole call, including any retries.
+func (c *Client) traceLayer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if c.Tracer == nil {
+			return next.Do(req)
+		}
+
+		info, _ := RequestInfoFromContext(req.Context())
+		ctx, span := c.startSpan(req.Context(), req, info)
+
+		resp, err := next.Do(req.WithContext(ctx))
+
+		if resp != nil {
+			span.SetAttribute("http.status_code", resp.StatusCode)
+		}
+
+		span.End(responseError(resp, err))
+
+		return resp, err
+	})
+}
+
+//authLayer sends authenticated requests with an access token that is refreshed beforehand when it
+//is about to expire. If Epic reports that the token is no longer valid, the token is refreshed and
+//the request is sent once more.
+func (c *Client) authLayer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		info, _ := RequestInfoFromContext(req.Context())
+
+		if !info.Authenticated {
+			return next.Do(req)
+		}
+
+		accessToken, err := c.accessToken(req.Context())
+
+		if err != nil {
+			return nil, err
+		}
+
+		authReq, err := withBearer(req, accessToken)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := next.Do(authReq)
+
+		if err != nil || resp.StatusCode != http.StatusUnauthorized || !isTokenRejected(responseError(resp, nil)) {
+			return resp, err
+		}
+
+		resp.Body.Close()
+
+		if err := c.refresh(req.Context(), accessToken); err != nil {
+			return nil, err
+		}
+
+		accessToken, _, _ = c.Tokens()
+
+		if authReq, err = withBearer(req, accessToken); err != nil {
+			return nil, err
+		}
+
+		return next.Do(authReq)
+	})
+}
+
+//retryLayer repeats idempotent requests that fail transiently or are throttled, according to the
+//client's RetryPolicy.
+func (c *Client) retryLayer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			return next.Do(req)
+		}
+
+		for attempt := 1; ; attempt++ {
+			resp, err := next.Do(req)
+			failure := responseError(resp, err)
+
+			if failure == nil {
+				return resp, err
+			}
+
+			delay, retry := c.RetryPolicy.wait(req.Context(), attempt, failure)
+
+			if !retry {
+				return resp, err
+			}
+
+			if resp != nil {
+				resp.Body.Close()
+			}
+
+			c.logRetry(req, attempt, delay, failure)
+
+			if err := sleep(req.Context(), delay); err != nil {
+				return nil, err
+			}
+		}
+	})
+}
+
+//rateLimitLayer holds each attempt back until the rate limit for its service allows it.
+func (c *Client) rateLimitLayer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		info, _ := RequestInfoFromContext(req.Context())
+
+		if err := c.limiters[info.Service].wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		return next.Do(req)
+	})
+}
+
+//observeLayer logs every attempt and reports it to the client's Metrics.
+func (c *Client) observeLayer(next Doer) Doer {
+	return DoerFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.Do(req)
+		latency := time.Since(start)
+
+		statusCode := 0
+
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+
+		info, _ := RequestInfoFromContext(req.Context())
+
+		c.logRequest(req, statusCode, latency, err)
+		c.observeRequest(info, statusCode, latency)
+
+		return resp, err
+	})
+}
+
+//withBearer returns a copy of the request carrying the given access token, with a fresh copy of
+//its body so that it can be sent again.
+func withBearer(req *http.Request, accessToken string) (*http.Request, error) {
+	authReq := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+
+		if err != nil {
+			return nil, fmt.Errorf("fortnite: unable to resend request: %w", err)
+		}
+
+		authReq.Body = body
+	}
+
+	authReq.Header.Set("Authorization", fmt.Sprintf("bearer %v", accessToken))
+
+	return authReq, nil
+}
+
+//responseError returns err if the request failed outright, an UpstreamError if the response has a
+//non-2xx status, and nil otherwise. The response body is buffered so it can still be read afterwards.
+func responseError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if resp == nil || (resp.StatusCode >= 200 && resp.StatusCode <= 299) {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("fortnite: unable to read response: %w", err)
+	}
+
+	return newUpstreamError(resp.StatusCode, resp.Header, body)
+}
@@ -0,0 +1,96 @@
+package fortnite
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+//DefaultUserAgent is the User-Agent header sent with every request unless WithUserAgent is used.
+const DefaultUserAgent = "jryd-fortnite-go"
+
+//Option configures a Client created with NewClient.
+type Option func(*Client)
+
+//WithCredentials sets the email and password used by Login.
+func WithCredentials(email string, password string) Option {
+	return func(c *Client) {
+		c.Email = email
+		c.Password = password
+	}
+}
+
+//WithClientTokens sets the Client Launcher Token and Fortnite Client Token captured from the
+//launcher and game, as described in the README.
+func WithClientTokens(clientLauncherToken string, fortniteClientToken string) Option {
+	return func(c *Client) {
+		c.ClientLauncherToken = clientLauncherToken
+		c.FortniteClientToken = fortniteClientToken
+	}
+}
+
+//WithDeviceAuth sets device credentials to log in with instead of an email and password.
+func WithDeviceAuth(deviceAuth DeviceAuth) Option {
+	return func(c *Client) {
+		c.DeviceAuth = &deviceAuth
+	}
+}
+
+//WithHTTPClient sets the *http.Client used to send every request. A nil client sends requests
+//with http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+//WithTimeout limits how long a single HTTP request may take, including reading the response. It
+//applies to a copy of the HTTP client, so one passed to WithHTTPClient is left untouched.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+//WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+//WithLogger sets the logger the client reports to. Every request is traced at debug level, failed
+//requests at warn level, and retries at info level. By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+//WithRetryPolicy sets how idempotent requests are retried after a transient failure.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = policy
+	}
+}
+
+//WithEndpoints sets the base URLs of the Epic services the client talks to.
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(c *Client) {
+		c.Endpoints = endpoints
+	}
+}
+
+//WithTokenStore sets the TokenStore used to resume and persist the session.
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Client) {
+		c.TokenStore = store
+	}
+}
+
+//WithTokenRefreshSkew sets how long before it expires the access token is refreshed.
+func WithTokenRefreshSkew(skew time.Duration) Option {
+	return func(c *Client) {
+		c.TokenRefreshSkew = skew
+	}
+}
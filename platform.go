@@ -0,0 +1,72 @@
+package fortnite
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Platform identifies the platform or input type a set of stats was recorded on, as it appears in
+//Epic's stat names.
+type Platform string
+
+//Platforms and input types Epic reports stats for. Older stats are split by platform, while newer
+//ones are split by the input type the player used.
+const (
+	PlatformPC     Platform = "pc"
+	PlatformPS4    Platform = "ps4"
+	PlatformXB1    Platform = "xb1"
+	PlatformSwitch Platform = "switch"
+	PlatformMobile Platform = "mobile"
+
+	PlatformKeyboardMouse Platform = "keyboardmouse"
+	PlatformGamepad       Platform = "gamepad"
+	PlatformTouch         Platform = "touch"
+)
+
+//Platforms lists every Platform the client knows about.
+var Platforms = []Platform{
+	PlatformPC,
+	PlatformPS4,
+	PlatformXB1,
+	PlatformSwitch,
+	PlatformMobile,
+	PlatformKeyboardMouse,
+	PlatformGamepad,
+	PlatformTouch,
+}
+
+//ParsePlatform converts a platform name such as "pc" or "PS4" into a Platform, returning an error
+//matching ErrInvalidPlatform if it is not one the client knows about.
+func ParsePlatform(name string) (Platform, error) {
+	platform := Platform(strings.ToLower(strings.TrimSpace(name)))
+
+	if err := platform.validate(); err != nil {
+		return "", err
+	}
+
+	return platform, nil
+}
+
+//Valid indicates whether the platform is one the client knows about.
+func (p Platform) Valid() bool {
+	for _, platform := range Platforms {
+		if p == platform {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Platform) String() string {
+	return string(p)
+}
+
+//validate returns an error matching ErrInvalidPlatform if the platform is not valid.
+func (p Platform) validate() error {
+	if !p.Valid() {
+		return fmt.Errorf("%w %q", ErrInvalidPlatform, string(p))
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package fortnite
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//Service identifies one of the Epic services the client talks to.
+type Service string
+
+//The Epic services the client talks to.
+const (
+	ServiceAccount     Service = "account"
+	ServicePersona     Service = "persona"
+	ServiceFortnite    Service = "fortnite"
+	ServiceLightswitch Service = "lightswitch"
+	ServiceContent     Service = "content"
+)
+
+//RateLimit configures a token bucket: requests are allowed at Rate per second on average, with up
+//to Burst sent back to back after a quiet period.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+//RateLimitStats reports how much a service's rate limit has held requests back.
+type RateLimitStats struct {
+	//Requests is how many requests have passed through the limiter.
+	Requests int64
+
+	//Waits is how many of those requests had to wait.
+	Waits int64
+
+	//WaitTime is the total time requests have spent waiting.
+	WaitTime time.Duration
+}
+
+//WithRateLimit limits the requests every method of the client sends to the given service. Callers
+//block until the request is allowed or their context is done. Services without a limit are not
+//throttled by the client.
+func WithRateLimit(service Service, limit RateLimit) Option {
+	return func(c *Client) {
+		if c.limiters == nil {
+			c.limiters = map[Service]*rateLimiter{}
+		}
+
+		c.limiters[service] = newRateLimiter(limit)
+	}
+}
+
+//RateLimitStats returns the time spent waiting on each rate limited service.
+func (c *Client) RateLimitStats() map[Service]RateLimitStats {
+	stats := map[Service]RateLimitStats{}
+
+	for service, limiter := range c.limiters {
+		stats[service] = limiter.stats()
+	}
+
+	return stats
+}
+
+//rateLimiter is a token bucket shared by every request to one service.
+type rateLimiter struct {
+	limit RateLimit
+
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+	counts  RateLimitStats
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return &rateLimiter{
+		limit:   limit,
+		tokens:  float64(limit.Burst),
+		updated: time.Now(),
+	}
+}
+
+//wait blocks until the bucket allows another request. A nil rateLimiter never blocks. If ctx is
+//done first the reserved token is handed back and the context's error is returned.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil || l.limit.Rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.updated).Seconds() * l.limit.Rate
+
+	if l.tokens > float64(l.limit.Burst) {
+		l.tokens = float64(l.limit.Burst)
+	}
+
+	l.updated = now
+	l.tokens--
+	l.counts.Requests++
+
+	var delay time.Duration
+
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.limit.Rate * float64(time.Second))
+		l.counts.Waits++
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := sleep(ctx, delay)
+
+	l.mu.Lock()
+	l.counts.WaitTime += time.Since(start)
+
+	if err != nil {
+		l.tokens++
+	}
+	l.mu.Unlock()
+
+	return err
+}
+
+func (l *rateLimiter) stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.counts
+}
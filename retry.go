@@ -0,0 +1,154 @@
+package fortnite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//RetryPolicy controls how idempotent requests are retried after a transient failure.
+type RetryPolicy struct {
+	//MaxAttempts is the total number of attempts made, including the first. A value of one or
+	//less disables retries.
+	MaxAttempts int
+
+	//BaseDelay is the wait before the first retry. It doubles on every attempt after that.
+	BaseDelay time.Duration
+
+	//MaxDelay caps the wait between attempts. Zero means the wait is not capped.
+	MaxDelay time.Duration
+
+	//MaxRetryAfter is the longest wait Epic may ask for through Retry-After or a throttling
+	//error before the client gives up instead of waiting. Zero means any wait is honoured.
+	MaxRetryAfter time.Duration
+}
+
+//DefaultRetryPolicy is the RetryPolicy used by NewClient.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     250 * time.Millisecond,
+	MaxDelay:      5 * time.Second,
+	MaxRetryAfter: 30 * time.Second,
+}
+
+//backoff returns how long to wait after the given failed attempt, counting from one. The delay
+//doubles on every attempt and is jittered to between half and all of its value, so that many
+//clients failing together do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		if delay <= 0 || delay > math.MaxInt64/2 {
+			break
+		}
+
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return delay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+//wait returns how long to wait before retrying after err, and whether a retry should be made at
+//all. A wait requested by Epic is honoured as given unless it exceeds MaxRetryAfter or would run
+//past the context's deadline.
+func (p RetryPolicy) wait(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || !isRetryable(ctx, err) {
+		return 0, false
+	}
+
+	var upstream *UpstreamError
+
+	if !errors.As(err, &upstream) || upstream.RetryAfter <= 0 {
+		return p.backoff(attempt), true
+	}
+
+	if p.MaxRetryAfter > 0 && upstream.RetryAfter > p.MaxRetryAfter {
+		return 0, false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(upstream.RetryAfter).After(deadline) {
+		return 0, false
+	}
+
+	return upstream.RetryAfter, true
+}
+
+//isRetryable indicates whether a failed attempt may succeed if it is repeated: the request never
+//reached Epic, Epic failed with a server error, or Epic throttled the request.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var upstream *UpstreamError
+
+	if errors.As(err, &upstream) {
+		return upstream.StatusCode >= http.StatusInternalServerError || errors.Is(upstream, ErrRateLimited)
+	}
+
+	var transport *transportError
+
+	return errors.As(err, &transport)
+}
+
+//retryAfter works out how long Epic asked the client to wait before trying again, from either the
+//Retry-After header or the seconds given in the messageVars of a throttling error.
+func retryAfter(header http.Header, epicError EpicError) time.Duration {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if date, err := http.ParseTime(value); err == nil {
+			return time.Until(date)
+		}
+	}
+
+	if epicError.IsThrottled() {
+		for i := len(epicError.MessageVars) - 1; i >= 0; i-- {
+			if seconds, err := strconv.Atoi(epicError.MessageVars[i]); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return 0
+}
+
+//transportError marks a request that failed before a response was received.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return "fortnite: request failed: " + e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+//sleep waits for d, returning early with the context's error if it is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
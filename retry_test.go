@@ -0,0 +1,92 @@
+package fortnite
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		max     time.Duration
+	}{
+		{"first attempt", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 1, 100 * time.Millisecond},
+		{"doubles", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 3, 400 * time.Millisecond},
+		{"capped", RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, 10, time.Second},
+		{"uncapped doubles", RetryPolicy{BaseDelay: 100 * time.Millisecond}, 4, 800 * time.Millisecond},
+		{"no base delay", RetryPolicy{MaxDelay: time.Second}, 3, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				delay := test.policy.backoff(test.attempt)
+
+				if delay < test.max/2 || delay > test.max {
+					t.Fatalf("backoff(%v) = %v, want between %v and %v", test.attempt, delay, test.max/2, test.max)
+				}
+			}
+		})
+	}
+
+	if delay := (RetryPolicy{BaseDelay: time.Second}).backoff(200); delay < time.Duration(math.MaxInt64/4) {
+		t.Errorf("uncapped backoff(200) = %v, want a large positive delay rather than an overflow", delay)
+	}
+}
+
+func TestWait(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, MaxRetryAfter: 10 * time.Second}
+
+	throttled := func(retryAfter time.Duration) error {
+		return &UpstreamError{StatusCode: http.StatusTooManyRequests, RetryAfter: retryAfter}
+	}
+
+	withDeadline := func(d time.Duration) context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), d)
+		t.Cleanup(cancel)
+
+		return ctx
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		attempt   int
+		err       error
+		wantRetry bool
+		wantDelay time.Duration
+	}{
+		{"server error backs off", context.Background(), 1, &UpstreamError{StatusCode: http.StatusBadGateway}, true, -1},
+		{"transport error backs off", context.Background(), 1, &transportError{err: errors.New("reset")}, true, -1},
+		{"client error", context.Background(), 1, &UpstreamError{StatusCode: http.StatusNotFound}, false, 0},
+		{"attempts exhausted", context.Background(), 3, &UpstreamError{StatusCode: http.StatusBadGateway}, false, 0},
+		{"retry after honoured", context.Background(), 1, throttled(2 * time.Second), true, 2 * time.Second},
+		{"retry after above MaxRetryAfter", context.Background(), 1, throttled(time.Minute), false, 0},
+		{"retry after past deadline", withDeadline(time.Second), 1, throttled(5 * time.Second), false, 0},
+		{"retry after within deadline", withDeadline(time.Minute), 1, throttled(5 * time.Second), true, 5 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			delay, retry := policy.wait(test.ctx, test.attempt, test.err)
+
+			if retry != test.wantRetry {
+				t.Fatalf("wait retry = %v, want %v", retry, test.wantRetry)
+			}
+
+			if test.wantDelay >= 0 && delay != test.wantDelay {
+				t.Errorf("wait delay = %v, want %v", delay, test.wantDelay)
+			}
+
+			if test.wantDelay < 0 && (delay < 50*time.Millisecond || delay > 100*time.Millisecond) {
+				t.Errorf("wait delay = %v, want a backoff between 50ms and 100ms", delay)
+			}
+		})
+	}
+}
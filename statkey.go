@@ -0,0 +1,82 @@
+package fortnite
+
+import (
+	"strings"
+)
+
+//Mode is the team size a playlist is played in.
+type Mode string
+
+//Modes that stats are grouped into. Playlists that are not recognised, such as Limited Time Modes,
+//are reported under a Mode named after the playlist.
+const (
+	ModeSolo  Mode = "solo"
+	ModeDuo   Mode = "duo"
+	ModeSquad Mode = "squad"
+)
+
+//playlistModes maps the playlists the client recognises onto the mode they are grouped under.
+//The p2, p10 and p9 playlists are the names used by the older stat keys.
+var playlistModes = map[string]Mode{
+	"p2":           ModeSolo,
+	"p10":          ModeDuo,
+	"p9":           ModeSquad,
+	"defaultsolo":  ModeSolo,
+	"defaultduo":   ModeDuo,
+	"defaultsquad": ModeSquad,
+}
+
+//PlaylistMode returns the mode a playlist is grouped under, and false if the playlist is not one
+//the client recognises, such as a Limited Time Mode or Playground.
+func PlaylistMode(playlist string) (Mode, bool) {
+	mode, ok := playlistModes[playlist]
+
+	return mode, ok
+}
+
+//StatKey is the parsed name of a single Battle Royale stat, such as
+//br_kills_keyboardmouse_m0_playlist_defaultsolo or the older br_kills_pc_m0_p2.
+type StatKey struct {
+	//Stat is what was counted, such as "kills", "placetop1" or "matchesplayed".
+	Stat string
+
+	Platform Platform
+
+	//Playlist is the playlist the stat was recorded in, such as "defaultsolo" or "p2".
+	Playlist string
+}
+
+//ParseStatKey parses a Battle Royale stat name, returning false if it is not in a format the
+//client understands.
+func ParseStatKey(name string) (StatKey, bool) {
+	parts := strings.Split(name, "_")
+
+	if len(parts) < 5 || parts[0] != "br" || parts[3] != "m0" || parts[1] == "" || parts[2] == "" {
+		return StatKey{}, false
+	}
+
+	key := StatKey{Stat: parts[1], Platform: Platform(parts[2])}
+
+	switch {
+	case len(parts) == 5 && parts[4] != "playlist":
+		key.Playlist = parts[4]
+	case len(parts) > 5 && parts[4] == "playlist":
+		key.Playlist = strings.Join(parts[5:], "_")
+	default:
+		return StatKey{}, false
+	}
+
+	if key.Playlist == "" {
+		return StatKey{}, false
+	}
+
+	return key, true
+}
+
+//PlaylistStats holds the stats recorded in a single playlist.
+type PlaylistStats struct {
+	//Mode is the mode the playlist is grouped under, or empty if the playlist is not recognised.
+	Mode Mode `json:"mode,omitempty"`
+
+	ModeStats
+}
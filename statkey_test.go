@@ -0,0 +1,42 @@
+package fortnite
+
+import (
+	"testing"
+)
+
+func TestParseStatKey(t *testing.T) {
+	tests := []struct {
+		name string
+		want StatKey
+		ok   bool
+	}{
+		{"br_kills_pc_m0_p2", StatKey{Stat: "kills", Platform: PlatformPC, Playlist: "p2"}, true},
+		{"br_placetop1_ps4_m0_p9", StatKey{Stat: "placetop1", Platform: PlatformPS4, Playlist: "p9"}, true},
+		{"br_kills_keyboardmouse_m0_playlist_defaultsolo", StatKey{Stat: "kills", Platform: PlatformKeyboardMouse, Playlist: "defaultsolo"}, true},
+		{"br_matchesplayed_gamepad_m0_playlist_playground_ltm_v2", StatKey{Stat: "matchesplayed", Platform: PlatformGamepad, Playlist: "playground_ltm_v2"}, true},
+
+		//Platforms are not checked here, so that callers can decide what to do with new ones.
+		{"br_kills_toaster_m0_p2", StatKey{Stat: "kills", Platform: "toaster", Playlist: "p2"}, true},
+
+		{"", StatKey{}, false},
+		{"br_kills_pc_m0", StatKey{}, false},
+		{"br_kills_pc_m1_p2", StatKey{}, false},
+		{"stw_kills_pc_m0_p2", StatKey{}, false},
+		{"br__pc_m0_p2", StatKey{}, false},
+		{"br_kills__m0_p2", StatKey{}, false},
+		{"br_kills_pc_m0_", StatKey{}, false},
+		{"br_kills_pc_m0_playlist", StatKey{}, false},
+		{"br_kills_pc_m0_playlist_", StatKey{}, false},
+		{"br_kills_pc_m0_p2_extra", StatKey{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := ParseStatKey(test.name)
+
+			if ok != test.ok || got != test.want {
+				t.Errorf("ParseStatKey(%q) = %+v, %v, want %+v, %v", test.name, got, ok, test.want, test.ok)
+			}
+		})
+	}
+}
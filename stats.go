@@ -0,0 +1,129 @@
+package fortnite
+
+//ModeStats holds the Battle Royale stats of a single mode, or of several modes added together.
+//The counters are what Epic records; the remaining fields are derived from them.
+type ModeStats struct {
+	Wins       float64 `json:"wins"`
+	Top3       float64 `json:"top3"`
+	Top5       float64 `json:"top5"`
+	Top6       float64 `json:"top6"`
+	Top10      float64 `json:"top10"`
+	Top12      float64 `json:"top12"`
+	Top25      float64 `json:"top25"`
+	Matches    float64 `json:"matches"`
+	Kills      float64 `json:"kills"`
+	TimePlayed float64 `json:"time_played"`
+	Score      float64 `json:"score"`
+
+	//SoloWins is the number of Wins that were in solo playlists, where winning means the player
+	//was never eliminated.
+	SoloWins float64 `json:"solo_wins"`
+
+	KdRatio                        float64 `json:"kd_ratio"`
+	WinPercentage                  float64 `json:"win_percentage"`
+	TimePlayedFormatted            string  `json:"time_played_formatted"`
+	KillsPerMatch                  float64 `json:"kills_per_match"`
+	KillsPerMin                    float64 `json:"kills_per_min"`
+	Deaths                         float64 `json:"deaths"`
+	KillsPerDeathExcludingSoloWins float64 `json:"kills_per_death_excluding_solo_wins"`
+	Top3Rate                       float64 `json:"top3_rate"`
+	Top5Rate                       float64 `json:"top5_rate"`
+	Top10Rate                      float64 `json:"top10_rate"`
+	Top25Rate                      float64 `json:"top25_rate"`
+	ScorePerMatch                  float64 `json:"score_per_match"`
+	ScorePerMin                    float64 `json:"score_per_min"`
+	MinutesPerMatch                float64 `json:"minutes_per_match"`
+}
+
+//Add returns the sum of two sets of stats, with the derived stats recalculated from the summed
+//counters.
+func (s ModeStats) Add(other ModeStats) ModeStats {
+	return s.combine(other, 1)
+}
+
+//Sub returns the difference between two sets of stats, with the derived stats recalculated from
+//the remaining counters.
+func (s ModeStats) Sub(other ModeStats) ModeStats {
+	return s.combine(other, -1)
+}
+
+//Totals returns the sum of any number of sets of stats.
+func Totals(stats ...ModeStats) ModeStats {
+	var total ModeStats
+
+	for _, s := range stats {
+		total = total.Add(s)
+	}
+
+	total.derive()
+
+	return total
+}
+
+//combine adds sign times other's counters to s.
+func (s ModeStats) combine(other ModeStats, sign float64) ModeStats {
+	result := ModeStats{
+		Wins:       s.Wins + sign*other.Wins,
+		Top3:       s.Top3 + sign*other.Top3,
+		Top5:       s.Top5 + sign*other.Top5,
+		Top6:       s.Top6 + sign*other.Top6,
+		Top10:      s.Top10 + sign*other.Top10,
+		Top12:      s.Top12 + sign*other.Top12,
+		Top25:      s.Top25 + sign*other.Top25,
+		Matches:    s.Matches + sign*other.Matches,
+		Kills:      s.Kills + sign*other.Kills,
+		TimePlayed: s.TimePlayed + sign*other.TimePlayed,
+		Score:      s.Score + sign*other.Score,
+		SoloWins:   s.SoloWins + sign*other.SoloWins,
+	}
+
+	result.derive()
+
+	return result
+}
+
+//derive calculates the derived stats from the counters.
+func (s *ModeStats) derive() {
+	s.KdRatio = ratio(s.Kills, s.Matches-s.Wins)
+	s.WinPercentage = percentage(s.Wins, s.Matches)
+	s.TimePlayedFormatted = formatTimeString(s.TimePlayed)
+	s.KillsPerMatch = ratio(s.Kills, s.Matches)
+	s.KillsPerMin = ratio(s.Kills, s.TimePlayed)
+	s.Deaths = s.Matches - s.SoloWins
+	s.KillsPerDeathExcludingSoloWins = ratio(s.Kills, s.Deaths)
+	s.Top3Rate = percentage(s.Top3, s.Matches)
+	s.Top5Rate = percentage(s.Top5, s.Matches)
+	s.Top10Rate = percentage(s.Top10, s.Matches)
+	s.Top25Rate = percentage(s.Top25, s.Matches)
+	s.ScorePerMatch = ratio(s.Score, s.Matches)
+	s.ScorePerMin = ratio(s.Score, s.TimePlayed)
+	s.MinutesPerMatch = ratio(s.TimePlayed, s.Matches)
+}
+
+//addStat records the value of a stat, ignoring stats the client does not use.
+func (s *ModeStats) addStat(stat string, value float64) {
+	switch stat {
+	case "placetop1":
+		s.Wins += value
+	case "placetop3":
+		s.Top3 += value
+	case "placetop5":
+		s.Top5 += value
+	case "placetop6":
+		s.Top6 += value
+	case "placetop10":
+		s.Top10 += value
+	case "placetop12":
+		s.Top12 += value
+	case "placetop25":
+		s.Top25 += value
+	case "matchesplayed":
+		s.Matches += value
+	case "kills":
+		s.Kills += value
+	case "score":
+		s.Score += value
+	case "minutesplayed":
+		s.TimePlayed += value
+	}
+}
@@ -0,0 +1,35 @@
+package fortnite
+
+import (
+	"testing"
+)
+
+func TestDeaths(t *testing.T) {
+	tests := []struct {
+		name          string
+		stats         ModeStats
+		deaths        float64
+		killsPerDeath float64
+	}{
+		{"no matches", ModeStats{}, 0, 0},
+		{"solo wins survived", ModeStats{Matches: 10, Wins: 2, SoloWins: 2, Kills: 16}, 8, 2},
+		{"team wins can still be deaths", ModeStats{Matches: 10, Wins: 4, Kills: 20}, 10, 2},
+		{"solo and team wins together", ModeStats{Matches: 20, Wins: 5, SoloWins: 2, Kills: 27}, 18, 1.5},
+		{"every match a solo win", ModeStats{Matches: 3, Wins: 3, SoloWins: 3, Kills: 9}, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stats := test.stats
+			stats.derive()
+
+			if stats.Deaths != test.deaths {
+				t.Errorf("Deaths = %v, want %v", stats.Deaths, test.deaths)
+			}
+
+			if stats.KillsPerDeathExcludingSoloWins != test.killsPerDeath {
+				t.Errorf("KillsPerDeathExcludingSoloWins = %v, want %v", stats.KillsPerDeathExcludingSoloWins, test.killsPerDeath)
+			}
+		})
+	}
+}
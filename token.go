@@ -0,0 +1,303 @@
+package fortnite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+//DefaultTokenRefreshSkew is how long before it expires that NewClient configures the access token
+//to be refreshed.
+const DefaultTokenRefreshSkew = time.Minute
+
+//Permission actions are a bitmask; a Permission grants every action whose bit is set.
+const (
+	ActionCreate = 1
+	ActionRead   = 2
+	ActionUpdate = 4
+	ActionDelete = 8
+)
+
+//Permission is a single grant held by an access token. Resource segments are separated by colons
+//and a segment of "*" matches any value.
+type Permission struct {
+	Resource string `json:"resource"`
+	Action   int    `json:"action"`
+}
+
+//TokenInfo is used to unmarshal the JSON response received after successfully verifying an
+//access token.
+type TokenInfo struct {
+	Token          string       `json:"token"`
+	SessionID      string       `json:"session_id"`
+	TokenType      string       `json:"token_type"`
+	ClientID       string       `json:"client_id"`
+	InternalClient bool         `json:"internal_client"`
+	ClientService  string       `json:"client_service"`
+	AccountID      string       `json:"account_id"`
+	ExpiresIn      int          `json:"expires_in"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	AuthMethod     string       `json:"auth_method"`
+	DisplayName    string       `json:"display_name"`
+	App            string       `json:"app"`
+	InAppID        string       `json:"in_app_id"`
+	DeviceID       string       `json:"device_id"`
+	Perms          []Permission `json:"perms"`
+}
+
+//HasPermission indicates whether the token is allowed to perform every action in the action
+//bitmask on the given resource.
+func (t TokenInfo) HasPermission(resource string, action int) bool {
+	granted := 0
+
+	for _, perm := range t.Perms {
+		if resourceMatches(perm.Resource, resource) {
+			granted |= perm.Action
+		}
+	}
+
+	return granted&action == action
+}
+
+//VerifyToken asks Epic to introspect the client's current access token, returning who it belongs
+//to, when it expires and the permissions it holds. It is useful as a health check of the session.
+func (c *Client) VerifyToken(ctx context.Context) (TokenInfo, error) {
+	var response TokenInfo
+
+	err := c.sendAuthorized(ctx, request{
+		method:   http.MethodGet,
+		url:      c.endpoints().oauthVerify(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+	}, &response)
+
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	return response, nil
+}
+
+//resourceMatches reports whether a permission resource pattern covers resource.
+func resourceMatches(pattern string, resource string) bool {
+	patternSegments := strings.Split(pattern, ":")
+	resourceSegments := strings.Split(resource, ":")
+
+	if len(patternSegments) != len(resourceSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if segment != "*" && segment != resourceSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//tokenRefresh tracks a refresh in progress so that concurrent callers wait for its result rather
+//than each starting their own.
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+//accessToken returns an access token that will remain valid for at least TokenRefreshSkew,
+//refreshing it or logging in again if required.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	accessToken, expiresAt, _ := c.Tokens()
+
+	if accessToken != "" && time.Now().Add(c.TokenRefreshSkew).Before(expiresAt) {
+		return accessToken, nil
+	}
+
+	if err := c.refresh(ctx, accessToken); err != nil {
+		return "", err
+	}
+
+	accessToken, _, _ = c.Tokens()
+
+	return accessToken, nil
+}
+
+//refreshTimeout bounds how long a shared refresh may run once it has been detached from the
+//context of the caller that started it.
+const refreshTimeout = 30 * time.Second
+
+//refresh replaces the stale access token. Only one refresh runs at a time; callers arriving while
+//one is in flight wait for it, and callers whose stale token has already been replaced return
+//straight away. The refresh runs detached from the caller that started it, so cancelling one
+//caller's context only stops that caller waiting and does not fail the refresh for the others.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.mu.Lock()
+
+	if c.AccessToken != stale {
+		c.mu.Unlock()
+		return nil
+	}
+
+	inFlight := c.refreshing
+
+	if inFlight == nil {
+		inFlight = &tokenRefresh{done: make(chan struct{})}
+		c.refreshing = inFlight
+
+		go c.runRefresh(context.WithoutCancel(ctx), inFlight)
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-inFlight.done:
+		return inFlight.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//runRefresh performs a refresh shared by every caller waiting on inFlight.
+func (c *Client) runRefresh(ctx context.Context, inFlight *tokenRefresh) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	inFlight.err = c.renew(ctx)
+
+	c.mu.Lock()
+	c.refreshing = nil
+	c.mu.Unlock()
+	close(inFlight.done)
+}
+
+//renew obtains a new access token, first trying a session saved in the TokenStore, then the
+//refresh token, and falling back to logging in again when there is no usable refresh token or
+//Epic no longer accepts it.
+func (c *Client) renew(ctx context.Context) error {
+	resumed, err := c.resumeSession(ctx)
+
+	if err != nil || resumed {
+		return err
+	}
+
+	c.mu.RLock()
+	refreshToken := c.RefreshToken
+	refreshExpired := !c.RefreshTokenExpiresAt.IsZero() && time.Now().After(c.RefreshTokenExpiresAt)
+	c.mu.RUnlock()
+
+	if refreshToken == "" || refreshExpired {
+		return c.relogin(ctx)
+	}
+
+	var response OauthTokenResponse
+
+	err = c.send(ctx, request{
+		method:   http.MethodPost,
+		url:      c.endpoints().oauthToken(),
+		service:  ServiceAccount,
+		endpoint: EndpointOAuth,
+		header:   http.Header{"Authorization": {basicAuth(c.FortniteClientToken)}},
+		form: OauthRefreshTokenRequest{
+			GrantType:    "refresh_token",
+			RefreshToken: refreshToken,
+			IncludePerms: true,
+		},
+	}, &response)
+
+	var upstream *UpstreamError
+
+	if errors.As(err, &upstream) && upstream.IsInvalidGrant() {
+		return c.relogin(ctx)
+	}
+
+	if err != nil {
+		return fmt.Errorf("fortnite: refresh token request: %w", err)
+	}
+
+	return c.setTokens(ctx, response)
+}
+
+//Resume restores the session saved in the TokenStore and makes sure it holds a usable access
+//token, refreshing it or logging in only when the stored tokens cannot be used. Authenticated
+//calls resume automatically, so Resume is only needed to restore the session ahead of time.
+func (c *Client) Resume(ctx context.Context) error {
+	if _, err := c.resumeSession(ctx); err != nil {
+		return err
+	}
+
+	_, err := c.accessToken(ctx)
+
+	return err
+}
+
+//Session returns the client's current tokens as a Session.
+func (c *Client) Session() Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return Session{
+		AccountID:             c.AccountID,
+		AccessToken:           c.AccessToken,
+		AccessTokenExpiresAt:  c.AccessTokenExpiresAt,
+		RefreshToken:          c.RefreshToken,
+		RefreshTokenExpiresAt: c.RefreshTokenExpiresAt,
+	}
+}
+
+//resumeSession adopts the session saved in the TokenStore when the client has no tokens of its
+//own yet. It reports whether the adopted access token can be used without refreshing it.
+func (c *Client) resumeSession(ctx context.Context) (bool, error) {
+	if c.TokenStore == nil {
+		return false, nil
+	}
+
+	c.mu.RLock()
+	hasTokens := c.AccessToken != "" || c.RefreshToken != ""
+	c.mu.RUnlock()
+
+	if hasTokens {
+		return false, nil
+	}
+
+	session, err := c.TokenStore.Load(ctx)
+
+	if errors.Is(err, ErrNoSession) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("fortnite: unable to load session: %w", err)
+	}
+
+	c.mu.Lock()
+	c.AccountID = session.AccountID
+	c.AccessToken = session.AccessToken
+	c.AccessTokenExpiresAt = session.AccessTokenExpiresAt
+	c.RefreshToken = session.RefreshToken
+	c.RefreshTokenExpiresAt = session.RefreshTokenExpiresAt
+	c.mu.Unlock()
+
+	return session.AccessToken != "" && time.Now().Add(c.TokenRefreshSkew).Before(session.AccessTokenExpiresAt), nil
+}
+
+//saveSession writes the client's current tokens to the TokenStore.
+func (c *Client) saveSession(ctx context.Context) error {
+	if c.TokenStore == nil {
+		return nil
+	}
+
+	if err := c.TokenStore.Save(ctx, c.Session()); err != nil {
+		return fmt.Errorf("fortnite: unable to save session: %w", err)
+	}
+
+	return nil
+}
+
+//isTokenRejected indicates whether err is Epic refusing the access token a request was sent with.
+func isTokenRejected(err error) bool {
+	var upstream *UpstreamError
+
+	return errors.As(err, &upstream) && upstream.StatusCode == http.StatusUnauthorized && upstream.IsTokenExpired()
+}
@@ -0,0 +1,129 @@
+package fortnite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//ErrNoSession is returned by a TokenStore when it has no session saved.
+var ErrNoSession = errors.New("fortnite: no stored session")
+
+//Session holds the OAuth tokens of a logged in account so that they can be persisted and resumed
+//after a restart.
+type Session struct {
+	AccountID             string    `json:"account_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+//TokenStore persists the Session of a Client. Save is called whenever the tokens change, including
+//with an empty Session once KillSession has invalidated them. Load returns ErrNoSession when there
+//is nothing to resume.
+type TokenStore interface {
+	Load(ctx context.Context) (Session, error)
+	Save(ctx context.Context, session Session) error
+}
+
+//MemoryTokenStore keeps the Session in memory. It is useful for sharing a session between
+//Clients in the same process.
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	session Session
+}
+
+//NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{}
+}
+
+//Load returns the saved Session.
+func (s *MemoryTokenStore) Load(ctx context.Context) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.session.AccessToken == "" && s.session.RefreshToken == "" {
+		return Session{}, ErrNoSession
+	}
+
+	return s.session, nil
+}
+
+//Save replaces the saved Session.
+func (s *MemoryTokenStore) Save(ctx context.Context, session Session) error {
+	s.mu.Lock()
+	s.session = session
+	s.mu.Unlock()
+
+	return nil
+}
+
+//FileTokenStore keeps the Session as JSON in a file that only the current user can read.
+type FileTokenStore struct {
+	Path string
+}
+
+//NewFileTokenStore returns a FileTokenStore that reads and writes the file at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{Path: path}
+}
+
+//Load reads the Session from the file.
+func (s *FileTokenStore) Load(ctx context.Context) (Session, error) {
+	data, err := os.ReadFile(s.Path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, ErrNoSession
+	}
+
+	if err != nil {
+		return Session{}, err
+	}
+
+	var session Session
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, err
+	}
+
+	if session.AccessToken == "" && session.RefreshToken == "" {
+		return Session{}, ErrNoSession
+	}
+
+	return session, nil
+}
+
+//Save writes the Session to a temporary file and renames it over the existing one, so a crash
+//part way through never leaves a truncated session behind.
+func (s *FileTokenStore) Save(ctx context.Context, session Session) error {
+	data, err := json.Marshal(session)
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.Path)
+}
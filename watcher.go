@@ -0,0 +1,229 @@
+package fortnite
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//DefaultWatchInterval is how often NewStatsWatcher configures active accounts to be polled.
+const DefaultWatchInterval = time.Minute
+
+//DefaultWatchMaxInterval is how far NewStatsWatcher configures polling of idle accounts to back off.
+const DefaultWatchMaxInterval = 15 * time.Minute
+
+//MatchFinished reports a match a watched player finished, as inferred from the change in their
+//stats between two polls.
+type MatchFinished struct {
+	AccountID string
+	Platform  Platform
+	Mode      Mode
+
+	//Placement is the best placement bucket the player finished in: 1 for a win, or 3, 5, 6, 10,
+	//12 or 25 for a top-N finish. It is zero when the player finished outside every bucket Epic
+	//tracks for the mode.
+	Placement int
+
+	Kills   float64
+	Score   float64
+	Minutes float64
+
+	//Estimated is set when several matches finished between two polls. Epic only reports the
+	//totals, so Kills, Score and Minutes are the average of those matches.
+	Estimated bool
+
+	//DetectedAt is when the poll that found the match completed.
+	DetectedAt time.Time
+}
+
+//StatsWatcher polls the all-time stats of a set of accounts and reports the matches they finish.
+//Epic does not expose match history, so matches are inferred from the difference between
+//consecutive snapshots. Accounts that have not finished a match recently are polled less often.
+type StatsWatcher struct {
+	//Interval is how often an account that has recently finished a match is polled.
+	Interval time.Duration
+
+	//MaxInterval is the longest an idle account goes without being polled. Each poll that finds
+	//no new matches doubles the account's interval, up to MaxInterval.
+	MaxInterval time.Duration
+
+	client     *Client
+	accountIDs []string
+	events     chan MatchFinished
+}
+
+//NewStatsWatcher creates a StatsWatcher for the given accounts using client for requests. Adjust
+//its fields before calling Run.
+func NewStatsWatcher(client *Client, accountIDs ...string) *StatsWatcher {
+	return &StatsWatcher{
+		Interval:    DefaultWatchInterval,
+		MaxInterval: DefaultWatchMaxInterval,
+		client:      client,
+		accountIDs:  accountIDs,
+		events:      make(chan MatchFinished, 16),
+	}
+}
+
+//Events returns the channel finished matches are sent on. It is closed when Run returns.
+func (w *StatsWatcher) Events() <-chan MatchFinished {
+	return w.events
+}
+
+//Run polls every account until ctx is cancelled, then closes the Events channel and returns the
+//context's error. The first poll of each account only records a baseline. Failed polls are logged
+//through the client's Logger and retried at the next interval. Run must only be called once.
+func (w *StatsWatcher) Run(ctx context.Context) error {
+	if w.Interval <= 0 {
+		w.Interval = DefaultWatchInterval
+	}
+
+	if w.MaxInterval < w.Interval {
+		w.MaxInterval = w.Interval
+	}
+
+	done := make(chan struct{})
+
+	for _, accountID := range w.accountIDs {
+		go func(accountID string) {
+			w.watch(ctx, accountID)
+			done <- struct{}{}
+		}(accountID)
+	}
+
+	for range w.accountIDs {
+		<-done
+	}
+
+	close(w.events)
+
+	return ctx.Err()
+}
+
+//watch polls a single account until ctx is cancelled.
+func (w *StatsWatcher) watch(ctx context.Context, accountID string) {
+	var previous *BRStatsByPlatform
+
+	interval := w.Interval
+
+	for {
+		current, err := w.client.GetStatsBRAllPlatformsFromID(ctx, accountID, StatsWindowAllTime)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			w.client.logger().Warn("stats watcher poll failed", "account_id", accountID, "error", err)
+		case previous == nil:
+			previous = &current
+		default:
+			matches := w.matchesFinished(*previous, current)
+			previous = &current
+
+			for _, match := range matches {
+				select {
+				case w.events <- match:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if len(matches) > 0 {
+				interval = w.Interval
+			} else if interval *= 2; interval > w.MaxInterval {
+				interval = w.MaxInterval
+			}
+		}
+
+		if sleep(ctx, interval) != nil {
+			return
+		}
+	}
+}
+
+//matchesFinished returns the matches finished between two snapshots of an account's stats.
+func (w *StatsWatcher) matchesFinished(before BRStatsByPlatform, after BRStatsByPlatform) []MatchFinished {
+	var matches []MatchFinished
+
+	detectedAt := time.Now()
+
+	for platform, stats := range after.Platforms {
+		previous, ok := before.Platforms[platform]
+
+		if !ok {
+			previous = FormattedBRStats{Info: stats.Info}
+		}
+
+		diff, err := Diff(previous, stats)
+
+		if errors.Is(err, ErrStatsReset) {
+			w.client.logger().Info("stats watcher detected a stats reset", "account_id", stats.Info.AccountID,
+				"platform", platform, "error", err)
+			continue
+		}
+
+		if err != nil {
+			w.client.logger().Warn("stats watcher unable to compare stats", "account_id", stats.Info.AccountID,
+				"platform", platform, "error", err)
+			continue
+		}
+
+		for mode, delta := range diff.Modes {
+			for _, match := range splitMatches(delta) {
+				match.AccountID = stats.Info.AccountID
+				match.Platform = platform
+				match.Mode = mode
+				match.DetectedAt = detectedAt
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	return matches
+}
+
+//splitMatches divides the change in a mode's stats into individual matches, assigning the best
+//placements first and sharing kills, score and minutes evenly between them.
+func splitMatches(delta ModeStats) []MatchFinished {
+	count := int(delta.Matches)
+
+	if count <= 0 {
+		return nil
+	}
+
+	buckets := []struct {
+		placement int
+		finishes  float64
+	}{
+		{1, delta.Wins},
+		{3, delta.Top3},
+		{5, delta.Top5},
+		{6, delta.Top6},
+		{10, delta.Top10},
+		{12, delta.Top12},
+		{25, delta.Top25},
+	}
+
+	matches := make([]MatchFinished, count)
+	n := float64(count)
+
+	for i := range matches {
+		matches[i] = MatchFinished{
+			Kills:     delta.Kills / n,
+			Score:     delta.Score / n,
+			Minutes:   delta.TimePlayed / n,
+			Estimated: count > 1,
+		}
+	}
+
+	//Top-N counters include every better finish, so each bucket only adds the finishes not already
+	//accounted for by a better one.
+	assigned := 0
+
+	for _, bucket := range buckets {
+		for finishes := int(bucket.finishes); assigned < finishes && assigned < count; assigned++ {
+			matches[assigned].Placement = bucket.placement
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,132 @@
+package fortnite
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSplitMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		delta      ModeStats
+		placements []int
+		kills      float64
+		estimated  bool
+	}{
+		{"no matches", ModeStats{Kills: 3}, nil, 0, false},
+		{"single win", ModeStats{Matches: 1, Wins: 1, Top10: 1, Top25: 1, Kills: 6}, []int{1}, 6, false},
+		{"single match outside every bucket", ModeStats{Matches: 1, Kills: 2}, []int{0}, 2, false},
+		{
+			name:       "best placements first",
+			delta:      ModeStats{Matches: 4, Wins: 1, Top10: 2, Top25: 3, Kills: 10},
+			placements: []int{1, 10, 25, 0},
+			kills:      2.5,
+			estimated:  true,
+		},
+		{
+			name:       "team modes",
+			delta:      ModeStats{Matches: 3, Top3: 1, Top6: 3, Kills: 3},
+			placements: []int{3, 6, 6},
+			kills:      1,
+			estimated:  true,
+		},
+		{
+			name:       "counters above the matches played",
+			delta:      ModeStats{Matches: 2, Top25: 5},
+			placements: []int{25, 25},
+			estimated:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matches := splitMatches(test.delta)
+
+			var placements []int
+
+			for _, match := range matches {
+				placements = append(placements, match.Placement)
+
+				if match.Kills != test.kills {
+					t.Errorf("Kills = %v, want %v", match.Kills, test.kills)
+				}
+
+				if match.Estimated != test.estimated {
+					t.Errorf("Estimated = %v, want %v", match.Estimated, test.estimated)
+				}
+			}
+
+			if !reflect.DeepEqual(placements, test.placements) {
+				t.Errorf("placements = %v, want %v", placements, test.placements)
+			}
+		})
+	}
+}
+
+func TestMatchesFinished(t *testing.T) {
+	watcher := NewStatsWatcher(NewClient(), "account-id")
+
+	pc := map[Mode]ModeStats{
+		ModeSolo:  {Matches: 10, Wins: 1, SoloWins: 1, Kills: 12},
+		ModeDuo:   {Matches: 4},
+		ModeSquad: {},
+	}
+
+	before := BRStatsByPlatform{Platforms: map[Platform]FormattedBRStats{
+		PlatformPC:  snapshot(PlatformPC, StatsWindowAllTime, pc),
+		PlatformPS4: snapshot(PlatformPS4, StatsWindowAllTime, pc),
+	}}
+
+	after := BRStatsByPlatform{Platforms: map[Platform]FormattedBRStats{
+		//One more solo match on PC.
+		PlatformPC: snapshot(PlatformPC, StatsWindowAllTime, map[Mode]ModeStats{
+			ModeSolo:  {Matches: 11, Wins: 1, SoloWins: 1, Top25: 1, Kills: 15},
+			ModeDuo:   {Matches: 4},
+			ModeSquad: {},
+		}),
+
+		//Stats on PS4 were reset, so no matches are reported for it.
+		PlatformPS4: snapshot(PlatformPS4, StatsWindowAllTime, map[Mode]ModeStats{
+			ModeSolo:  {},
+			ModeDuo:   {},
+			ModeSquad: {},
+		}),
+
+		//The player's first matches on a gamepad.
+		PlatformGamepad: snapshot(PlatformGamepad, StatsWindowAllTime, map[Mode]ModeStats{
+			ModeSolo:  {},
+			ModeDuo:   {},
+			ModeSquad: {Matches: 2, Top3: 1, Top6: 2, Kills: 4},
+		}),
+	}}
+
+	matches := watcher.matchesFinished(before, after)
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Platform != matches[j].Platform {
+			return matches[i].Platform < matches[j].Platform
+		}
+
+		return matches[i].Placement < matches[j].Placement
+	})
+
+	want := []MatchFinished{
+		{AccountID: "account-id", Platform: PlatformGamepad, Mode: ModeSquad, Placement: 3, Kills: 2, Estimated: true},
+		{AccountID: "account-id", Platform: PlatformGamepad, Mode: ModeSquad, Placement: 6, Kills: 2, Estimated: true},
+		{AccountID: "account-id", Platform: PlatformPC, Mode: ModeSolo, Placement: 25, Kills: 3},
+	}
+
+	for i := range matches {
+		if matches[i].DetectedAt.IsZero() {
+			t.Errorf("match %v has no DetectedAt", i)
+		}
+
+		matches[i].DetectedAt = time.Time{}
+	}
+
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("matchesFinished() = %+v, want %+v", matches, want)
+	}
+}
@@ -0,0 +1,140 @@
+package fortnite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//StatsWindow selects the period Battle Royale stats cover. The zero value is StatsWindowAllTime.
+//Use StatsWindowSince or StatsWindowRange for a custom period. Epic offers no named season window
+//and no way to look up when a season started, so for the current season pass its start time, found
+//elsewhere, to StatsWindowSince.
+type StatsWindow struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+//Stats windows Epic provides by name.
+var (
+	StatsWindowAllTime = StatsWindow{name: "alltime"}
+	StatsWindowWeekly  = StatsWindow{name: "weekly"}
+)
+
+//StatsWindowSince returns a window covering the stats recorded since start. For the current
+//season, pass the time the season started; the client cannot look it up.
+func StatsWindowSince(start time.Time) StatsWindow {
+	return StatsWindow{start: start}
+}
+
+//StatsWindowRange returns a window covering the stats recorded between start and end.
+func StatsWindowRange(start time.Time, end time.Time) StatsWindow {
+	return StatsWindow{start: start, end: end}
+}
+
+//Start returns the start of a custom window, or the zero time for a named one.
+func (w StatsWindow) Start() time.Time {
+	return w.start
+}
+
+//End returns the end of a custom window, or the zero time if it is named or open-ended.
+func (w StatsWindow) End() time.Time {
+	return w.end
+}
+
+//IsCustom indicates whether the window is a time range rather than one of Epic's named windows.
+func (w StatsWindow) IsCustom() bool {
+	return w.name == "" && (!w.start.IsZero() || !w.end.IsZero())
+}
+
+//String returns the window's name, or its start and end times separated by a slash for a custom
+//window.
+func (w StatsWindow) String() string {
+	if !w.IsCustom() {
+		return w.named()
+	}
+
+	return formatWindowTime(w.start) + "/" + formatWindowTime(w.end)
+}
+
+//MarshalText encodes the window as returned by String.
+func (w StatsWindow) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+//UnmarshalText decodes a window encoded by MarshalText.
+func (w *StatsWindow) UnmarshalText(text []byte) error {
+	value := string(text)
+
+	switch value {
+	case "", StatsWindowAllTime.name:
+		*w = StatsWindowAllTime
+		return nil
+	case StatsWindowWeekly.name:
+		*w = StatsWindowWeekly
+		return nil
+	}
+
+	startText, endText, ok := strings.Cut(value, "/")
+
+	if !ok {
+		return fmt.Errorf("fortnite: unknown stats window %q", value)
+	}
+
+	start, err := parseWindowTime(startText)
+
+	if err != nil {
+		return err
+	}
+
+	end, err := parseWindowTime(endText)
+
+	if err != nil {
+		return err
+	}
+
+	*w = StatsWindowRange(start, end)
+
+	return nil
+}
+
+//named returns the name of a named window, defaulting to all-time.
+func (w StatsWindow) named() string {
+	if w.name == "" {
+		return StatsWindowAllTime.name
+	}
+
+	return w.name
+}
+
+//validate returns an error if a custom window ends before it starts.
+func (w StatsWindow) validate() error {
+	if w.IsCustom() && !w.end.IsZero() && w.end.Before(w.start) {
+		return fmt.Errorf("fortnite: stats window ends before it starts: %v", w)
+	}
+
+	return nil
+}
+
+func formatWindowTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseWindowTime(text string) (time.Time, error) {
+	if text == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, text)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fortnite: invalid stats window time: %w", err)
+	}
+
+	return t, nil
+}